@@ -0,0 +1,3 @@
+module calculator1
+
+go 1.21
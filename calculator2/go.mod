@@ -0,0 +1,3 @@
+module calculator2
+
+go 1.21
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// обмеження тривалості симуляції, днів
+const maxBatteryDays = 3650
+
+// параметри накопичувача енергії
+type BatteryParams struct {
+	Capacity   float64 `json:"capacity"`   // ємність, кВт·год
+	Power      float64 `json:"power"`      // номінальна потужність заряду/розряду, кВт
+	Efficiency float64 `json:"efficiency"` // ККД циклу заряд-розряд, %
+	InitialSoC float64 `json:"initialSoc"` // початковий рівень заряду, %
+}
+
+// вхідні дані симуляції станції з накопичувачем
+type BatteryRequest struct {
+	PlantParams
+	Market          string           `json:"market"`
+	Sigma           float64          `json:"sigma"`
+	Distribution    DistributionSpec `json:"distribution"`
+	Battery         BatteryParams    `json:"battery"`
+	Days            int              `json:"days"`
+	Seed            int64            `json:"seed"`
+	Autocorrelation float64          `json:"autocorrelation"`
+}
+
+// середньодобові показники симуляції
+type SimulationSummary struct {
+	ShareNoImbalance float64 `json:"shareNoImbalance"`
+	Revenue          float64 `json:"revenue"`
+	Penalty          float64 `json:"penalty"`
+	Profit           float64 `json:"profit"`
+}
+
+// порівняння станції без накопичувача та з ним
+type BatteryResult struct {
+	Market           string            `json:"market"`
+	Days             int               `json:"days"`
+	Baseline         ScenarioResult    `json:"baseline"` // аналітичний результат калькулятора
+	WithoutBattery   SimulationSummary `json:"withoutBattery"`
+	WithBattery      SimulationSummary `json:"withBattery"`
+	PenaltyReduction float64           `json:"penaltyReduction"` // грн/добу
+	PenaltyReduced   float64           `json:"penaltyReduced"`   // %
+	ExtraProfit      float64           `json:"extraProfit"`      // відносно симуляції без накопичувача, грн/добу
+	Cycles           float64           `json:"cycles"`           // еквівалентних повних циклів за період
+	Losses           float64           `json:"losses"`           // втрати в накопичувачі, кВт·год за період
+}
+
+// дані сторінки накопичувача
+type BatteryPageData struct {
+	Profiles []MarketRules
+	Error    string
+	Result   *BatteryResult
+}
+
+// стан накопичувача під час симуляції
+type batteryState struct {
+	params     BatteryParams
+	soc        float64 // енергія в накопичувачі, кВт·год
+	efficiency float64 // ККД в одному напрямку
+	throughput float64
+	losses     float64
+}
+
+// заряджає накопичувач надлишком energy і повертає прийняту енергію
+func (b *batteryState) charge(energy float64) float64 {
+	accepted := math.Min(energy, b.params.Power)
+	accepted = math.Min(accepted, (b.params.Capacity-b.soc)/b.efficiency)
+	if accepted <= 0 {
+		return 0
+	}
+	b.soc += accepted * b.efficiency
+	b.throughput += accepted
+	b.losses += accepted * (1 - b.efficiency)
+	return accepted
+}
+
+// розряджає накопичувач на величину дефіциту energy і повертає видану енергію
+func (b *batteryState) discharge(energy float64) float64 {
+	delivered := math.Min(energy, b.params.Power)
+	delivered = math.Min(delivered, b.soc*b.efficiency)
+	if delivered <= 0 {
+		return 0
+	}
+	b.soc -= delivered / b.efficiency
+	b.losses += delivered * (1/b.efficiency - 1)
+	return delivered
+}
+
+// тримає видачу станції в коридорі, а всередині коридору повертає заряд до половини ємності
+func (b *batteryState) dispatch(actual, lower, upper float64) float64 {
+	target := b.params.Capacity / 2
+	switch {
+	case actual > upper:
+		return actual - b.charge(actual-upper)
+	case actual < lower:
+		return actual + b.discharge(lower-actual)
+	case b.soc < target:
+		return actual - b.charge(math.Min(actual-lower, (target-b.soc)/b.efficiency))
+	case b.soc > target:
+		return actual + b.discharge(math.Min(upper-actual, (b.soc-target)*b.efficiency))
+	}
+	return actual
+}
+
+// перевіряє параметри накопичувача
+func (p BatteryParams) validate() error {
+	if p.Capacity <= 0 || p.Power <= 0 {
+		return errors.New("ємність і потужність накопичувача мають бути додатними")
+	}
+	if p.Efficiency <= 0 || p.Efficiency > 100 {
+		return errors.New("ККД накопичувача має бути в межах (0, 100] %")
+	}
+	return nil
+}
+
+// підсумовує години симуляції в середньодобові показники
+func summarizeSimulation(inBand, hours int, revenue, penalty float64, days int) SimulationSummary {
+	return SimulationSummary{
+		ShareNoImbalance: roundTwo(float64(inBand) / float64(hours) * 100),
+		Revenue:          roundTwo(revenue / float64(days)),
+		Penalty:          roundTwo(penalty / float64(days)),
+		Profit:           roundTwo((revenue - penalty) / float64(days)),
+	}
+}
+
+// симулює роботу станції з накопичувачем на тих самих реалізаціях похибок, що й без нього
+func simulateBattery(req BatteryRequest) (BatteryResult, error) {
+	if req.Power <= 0 || req.Sigma <= 0 {
+		return BatteryResult{}, errors.New("потужність і σ мають бути додатними")
+	}
+	if err := req.Battery.validate(); err != nil {
+		return BatteryResult{}, err
+	}
+	if req.Autocorrelation <= -1 || req.Autocorrelation >= 1 {
+		return BatteryResult{}, errors.New("коефіцієнт автокореляції має бути в межах (-1, 1)")
+	}
+	if err := req.Distribution.validate(); err != nil {
+		return BatteryResult{}, err
+	}
+	if req.Days <= 0 {
+		req.Days = 365
+	}
+	if req.Days > maxBatteryDays {
+		req.Days = maxBatteryDays
+	}
+	if req.Battery.InitialSoC <= 0 || req.Battery.InitialSoC > 100 {
+		req.Battery.InitialSoC = 50
+	}
+
+	rules := findMarketRules(req.Market)
+	params := req.PlantParams.withMarketDefaults(rules)
+
+	errorDistribution := req.Distribution.build(params.Power, req.Sigma)
+	hourErrors := newHourlyErrors(errorDistribution, req.Autocorrelation, req.Seed)
+	lower, upper := params.band()
+	hourRevenue := params.Power * params.Cost
+	hourPenalty := params.Power * params.PenaltyPrice
+
+	battery := batteryState{
+		params:     req.Battery,
+		soc:        req.Battery.Capacity * req.Battery.InitialSoC / 100,
+		efficiency: math.Sqrt(req.Battery.Efficiency / 100),
+	}
+
+	hours := req.Days * 24
+	hourErrors.start()
+	plainInBand, batteryInBand := 0, 0
+	plainRevenue, plainPenalty := 0.0, 0.0
+	batteryRevenue, batteryPenalty := 0.0, 0.0
+	for hour := 0; hour < hours; hour++ {
+		actual := params.Power + hourErrors.next()
+
+		if actual >= lower && actual <= upper {
+			plainInBand++
+			plainRevenue += hourRevenue
+		} else {
+			plainPenalty += hourPenalty
+		}
+
+		output := battery.dispatch(actual, lower, upper)
+		if output >= lower-1e-9 && output <= upper+1e-9 {
+			batteryInBand++
+			batteryRevenue += hourRevenue
+		} else {
+			batteryPenalty += hourPenalty
+		}
+	}
+
+	// енергія, втрачена в накопичувачі, не продається
+	batteryRevenue -= battery.losses * params.Cost
+
+	result := BatteryResult{
+		Market:         rules.Title,
+		Days:           req.Days,
+		Baseline:       calculateScenario(params, errorDistribution),
+		WithoutBattery: summarizeSimulation(plainInBand, hours, plainRevenue, plainPenalty, req.Days),
+		WithBattery:    summarizeSimulation(batteryInBand, hours, batteryRevenue, batteryPenalty, req.Days),
+		Cycles:         roundTwo(battery.throughput / req.Battery.Capacity),
+		Losses:         roundTwo(battery.losses),
+	}
+	result.PenaltyReduction = roundTwo(result.WithoutBattery.Penalty - result.WithBattery.Penalty)
+	if result.WithoutBattery.Penalty > 0 {
+		result.PenaltyReduced = roundTwo(result.PenaltyReduction / result.WithoutBattery.Penalty * 100)
+	}
+	result.ExtraProfit = roundTwo(result.WithBattery.Profit - result.WithoutBattery.Profit)
+	return result, nil
+}
+
+// Обробник сторінки станції з накопичувачем
+func handleBattery(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := template.ParseFiles("battery.html")
+	if err != nil {
+		http.Error(w, "Не вдалося завантажити сторінку", http.StatusInternalServerError)
+		return
+	}
+
+	data := BatteryPageData{Profiles: marketProfiles}
+
+	if r.Method == http.MethodPost {
+		r.ParseForm()
+		req := BatteryRequest{
+			Market:       r.FormValue("market"),
+			Distribution: parseDistributionSpec(r),
+		}
+		req.Power, _ = strconv.ParseFloat(r.FormValue("power"), 64)
+		req.Cost, _ = strconv.ParseFloat(r.FormValue("cost"), 64)
+		req.TolerancePercent, _ = strconv.ParseFloat(r.FormValue("tolerancePercent"), 64)
+		req.PenaltyPrice, _ = strconv.ParseFloat(r.FormValue("penaltyPrice"), 64)
+		req.Sigma, _ = strconv.ParseFloat(r.FormValue("sigma"), 64)
+		req.Battery.Capacity, _ = strconv.ParseFloat(r.FormValue("batteryCapacity"), 64)
+		req.Battery.Power, _ = strconv.ParseFloat(r.FormValue("batteryPower"), 64)
+		req.Battery.Efficiency, _ = strconv.ParseFloat(r.FormValue("batteryEfficiency"), 64)
+		req.Battery.InitialSoC, _ = strconv.ParseFloat(r.FormValue("batterySoc"), 64)
+		req.Days, _ = strconv.Atoi(r.FormValue("days"))
+		req.Seed, _ = strconv.ParseInt(r.FormValue("seed"), 10, 64)
+		req.Autocorrelation, _ = strconv.ParseFloat(r.FormValue("autocorrelation"), 64)
+
+		result, err := simulateBattery(req)
+		if err != nil {
+			data.Error = err.Error()
+		} else {
+			data.Result = &result
+		}
+	}
+	tmpl.Execute(w, data)
+}
+
+// Обробник API станції з накопичувачем
+func handleBatteryAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req BatteryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := simulateBattery(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func newTestBattery(soc float64) batteryState {
+	// ККД циклу 81 %, тобто 90 % в кожному напрямку
+	params := BatteryParams{Capacity: 2, Power: 1, Efficiency: 81}
+	return batteryState{params: params, soc: soc, efficiency: math.Sqrt(params.Efficiency / 100)}
+}
+
+// заряд обмежений потужністю та вільною ємністю, частина енергії втрачається
+func TestBatteryChargeLimits(t *testing.T) {
+	b := newTestBattery(0)
+	wantAccepted := []float64{1, 1, 0.2 / 0.9, 0}
+	for i, want := range wantAccepted {
+		if got := b.charge(5); math.Abs(got-want) > 1e-9 {
+			t.Errorf("заряд %d: прийнято %v кВт·год, очікувалось %v", i+1, got, want)
+		}
+		if b.soc > b.params.Capacity+1e-9 {
+			t.Fatalf("заряд %d: рівень заряду %v перевищує ємність", i+1, b.soc)
+		}
+	}
+	if math.Abs(b.soc-2) > 1e-9 || math.Abs(b.throughput-(2+0.2/0.9)) > 1e-9 {
+		t.Errorf("рівень заряду %v, обіг %v", b.soc, b.throughput)
+	}
+	if math.Abs(b.losses-(b.throughput-b.soc)) > 1e-9 {
+		t.Errorf("втрати %v, очікувалось %v", b.losses, b.throughput-b.soc)
+	}
+}
+
+// розряд обмежений потужністю та запасом енергії з урахуванням ККД
+func TestBatteryDischargeLimits(t *testing.T) {
+	b := newTestBattery(2)
+	wantDelivered := []float64{1, 0.8, 0}
+	for i, want := range wantDelivered {
+		if got := b.discharge(5); math.Abs(got-want) > 1e-9 {
+			t.Errorf("розряд %d: видано %v кВт·год, очікувалось %v", i+1, got, want)
+		}
+		if b.soc < -1e-9 {
+			t.Fatalf("розряд %d: від'ємний рівень заряду %v", i+1, b.soc)
+		}
+	}
+	// з 2 кВт·год видано 1,8 кВт·год: 10 % втрачено на виході
+	if math.Abs(b.losses-0.2) > 1e-9 {
+		t.Errorf("втрати %v, очікувалось 0.2", b.losses)
+	}
+}
+
+func TestBatteryRoundTripEfficiency(t *testing.T) {
+	b := newTestBattery(0)
+	accepted := b.charge(1)
+	delivered := b.discharge(5)
+	if math.Abs(delivered/accepted-0.81) > 1e-9 {
+		t.Errorf("ККД циклу %v, очікувалось 0.81", delivered/accepted)
+	}
+}
+
+// коридор 4,75–5,25 кВт
+func TestBatteryDispatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		soc    float64
+		actual float64
+		want   float64
+	}{
+		{"надлишок у межах потужності", 1, 6, 5.25},
+		{"надлишок понад потужність", 1, 7, 6},
+		{"надлишок при повному накопичувачі", 2, 6, 6},
+		{"дефіцит у межах потужності", 1, 4, 4.75},
+		{"дефіцит понад потужність", 2, 3, 4},
+		{"дефіцит понад запас енергії", 1, 3, 3.9},
+		{"дефіцит при порожньому накопичувачі", 0, 4, 4},
+		{"у коридорі: дозаряд до половини", 0.5, 5, 4.75},
+		{"у коридорі: розряд до половини", 1.5, 5, 5.25},
+		{"у коридорі при половині ємності", 1, 5, 5},
+	}
+	for _, tt := range tests {
+		b := newTestBattery(tt.soc)
+		if got := b.dispatch(tt.actual, 4.75, 5.25); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: видача %v кВт, очікувалось %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSimulateBatteryReducesPenaltyFixedSeed(t *testing.T) {
+	req := BatteryRequest{
+		PlantParams: PlantParams{Power: 5, TolerancePercent: 5, Cost: 7, PenaltyPrice: 7},
+		Sigma:       0.25,
+		Battery:     BatteryParams{Capacity: 2, Power: 1, Efficiency: 90},
+		Days:        365,
+		Seed:        1,
+	}
+	result, err := simulateBattery(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.WithoutBattery.Penalty != 272.71 || result.WithBattery.Penalty != 0.29 || result.PenaltyReduced != 99.89 {
+		t.Errorf("штраф без накопичувача %v, з ним %v (%v %%), очікувалось 272.71, 0.29 і 99.89 %%",
+			result.WithoutBattery.Penalty, result.WithBattery.Penalty, result.PenaltyReduced)
+	}
+	// обидві симуляції використовують ті самі реалізації похибок
+	if want := roundTwo(result.WithBattery.Profit - result.WithoutBattery.Profit); result.ExtraProfit != want {
+		t.Errorf("додатковий прибуток %v, очікувалось %v", result.ExtraProfit, want)
+	}
+
+	again, _ := simulateBattery(req)
+	if again != result {
+		t.Errorf("результат із зерном %d не відтворюється", req.Seed)
+	}
+}
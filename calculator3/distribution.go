@@ -0,0 +1,336 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// розподіл похибки прогнозу генерації (факт мінус прогноз)
+type Distribution interface {
+	Name() string
+	CDF(x float64) float64
+	StdDev() float64
+}
+
+// ймовірність потрапляння похибки в інтервал [lower, upper]
+func probabilityBetween(d Distribution, lower, upper float64) float64 {
+	return math.Max(0, d.CDF(upper)-d.CDF(lower))
+}
+
+// функція розподілу нормального закону через erf
+func normalCDF(x, mean, stdDev float64) float64 {
+	if stdDev <= 0 {
+		if x < mean {
+			return 0
+		}
+		return 1
+	}
+	return 0.5 * (1 + math.Erf((x-mean)/(stdDev*math.Sqrt2)))
+}
+
+// щільність стандартного нормального розподілу
+func standardNormalPDF(z float64) float64 {
+	return math.Exp(-0.5*z*z) / math.Sqrt(2*math.Pi)
+}
+
+// нормальний розподіл
+type NormalDistribution struct {
+	Mean  float64 `json:"mean"`
+	Sigma float64 `json:"sigma"`
+}
+
+func (d NormalDistribution) Name() string { return "Нормальний" }
+
+func (d NormalDistribution) CDF(x float64) float64 { return normalCDF(x, d.Mean, d.Sigma) }
+
+func (d NormalDistribution) StdDev() float64 { return d.Sigma }
+
+// нормальний розподіл, обмежений інтервалом [Lower, Upper]
+type TruncatedNormalDistribution struct {
+	Mean  float64
+	Sigma float64
+	Lower float64
+	Upper float64
+}
+
+func (d TruncatedNormalDistribution) Name() string { return "Усічений нормальний" }
+
+func (d TruncatedNormalDistribution) CDF(x float64) float64 {
+	if x <= d.Lower {
+		return 0
+	}
+	if x >= d.Upper {
+		return 1
+	}
+	low := normalCDF(d.Lower, d.Mean, d.Sigma)
+	norm := normalCDF(d.Upper, d.Mean, d.Sigma) - low
+	if norm <= 0 {
+		return normalCDF(x, d.Mean, d.Sigma)
+	}
+	return (normalCDF(x, d.Mean, d.Sigma) - low) / norm
+}
+
+func (d TruncatedNormalDistribution) StdDev() float64 {
+	if d.Sigma <= 0 {
+		return 0
+	}
+	alpha := (d.Lower - d.Mean) / d.Sigma
+	beta := (d.Upper - d.Mean) / d.Sigma
+	norm := normalCDF(beta, 0, 1) - normalCDF(alpha, 0, 1)
+	if norm <= 0 {
+		return d.Sigma
+	}
+	// z·φ(z) прямує до нуля на нескінченних межах
+	zPhi := func(z float64) float64 {
+		if math.IsInf(z, 0) {
+			return 0
+		}
+		return z * standardNormalPDF(z)
+	}
+	shift := (standardNormalPDF(alpha) - standardNormalPDF(beta)) / norm
+	variance := 1 + (zPhi(alpha)-zPhi(beta))/norm - shift*shift
+	return d.Sigma * math.Sqrt(math.Max(variance, 0))
+}
+
+// розподіл Стьюдента зі зсувом і масштабом
+type StudentTDistribution struct {
+	Location float64
+	Scale    float64
+	DOF      float64
+}
+
+func (d StudentTDistribution) Name() string { return "Стьюдента" }
+
+func (d StudentTDistribution) CDF(x float64) float64 {
+	if d.Scale <= 0 {
+		return normalCDF(x, d.Location, 0)
+	}
+	t := (x - d.Location) / d.Scale
+	tail := 0.5 * regularizedIncompleteBeta(d.DOF/2, 0.5, d.DOF/(d.DOF+t*t))
+	if t > 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+func (d StudentTDistribution) StdDev() float64 {
+	if d.DOF <= 2 {
+		return math.Inf(1)
+	}
+	return d.Scale * math.Sqrt(d.DOF/(d.DOF-2))
+}
+
+// регуляризована неповна бета-функція I_x(a, b)
+func regularizedIncompleteBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	lgab, _ := math.Lgamma(a + b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(a, b, x) / a
+	}
+	return 1 - front*betaContinuedFraction(b, a, 1-x)/b
+}
+
+// ланцюговий дріб для неповної бета-функції (метод Лентца)
+func betaContinuedFraction(a, b, x float64) float64 {
+	const maxIterations = 300
+	const eps = 1e-14
+	const tiny = 1e-300
+
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	result := d
+	for m := 1; m <= maxIterations; m++ {
+		fm := float64(m)
+		numerator := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 + numerator*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + numerator/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		result *= d * c
+
+		numerator = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 + numerator*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + numerator/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		result *= delta
+		if math.Abs(delta-1) < eps {
+			break
+		}
+	}
+	return result
+}
+
+// емпіричний розподіл, заданий гістограмою похибок
+type EmpiricalDistribution struct {
+	Edges  []float64 // межі інтервалів, на одну більше ніж Counts
+	Counts []float64
+}
+
+// будує гістограму з рівними інтервалами за вибіркою похибок
+func newEmpiricalDistribution(samples []float64, bins int) EmpiricalDistribution {
+	if len(samples) == 0 {
+		return EmpiricalDistribution{}
+	}
+	if bins <= 0 {
+		bins = int(math.Ceil(math.Sqrt(float64(len(samples)))))
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	minValue, maxValue := sorted[0], sorted[len(sorted)-1]
+	if maxValue == minValue {
+		minValue, maxValue = minValue-0.5, maxValue+0.5
+	}
+
+	width := (maxValue - minValue) / float64(bins)
+	edges := make([]float64, bins+1)
+	for i := range edges {
+		edges[i] = minValue + float64(i)*width
+	}
+	counts := make([]float64, bins)
+	for _, value := range sorted {
+		index := int((value - minValue) / width)
+		if index >= bins {
+			index = bins - 1
+		}
+		counts[index]++
+	}
+	return EmpiricalDistribution{Edges: edges, Counts: counts}
+}
+
+func (d EmpiricalDistribution) Name() string { return "Емпіричний" }
+
+func (d EmpiricalDistribution) total() float64 {
+	total := 0.0
+	for _, count := range d.Counts {
+		total += count
+	}
+	return total
+}
+
+// функція розподілу з лінійною інтерполяцією всередині інтервалу
+func (d EmpiricalDistribution) CDF(x float64) float64 {
+	total := d.total()
+	if total == 0 || x <= d.Edges[0] {
+		return 0
+	}
+	cumulative := 0.0
+	for i, count := range d.Counts {
+		left, right := d.Edges[i], d.Edges[i+1]
+		if x < right {
+			return (cumulative + count*(x-left)/(right-left)) / total
+		}
+		cumulative += count
+	}
+	return 1
+}
+
+func (d EmpiricalDistribution) mean() float64 {
+	total := d.total()
+	if total == 0 {
+		return 0
+	}
+	sum := 0.0
+	for i, count := range d.Counts {
+		sum += count * (d.Edges[i] + d.Edges[i+1]) / 2
+	}
+	return sum / total
+}
+
+// стандартне відхилення з урахуванням рівномірного розподілу всередині інтервалу
+func (d EmpiricalDistribution) StdDev() float64 {
+	total := d.total()
+	if total == 0 {
+		return 0
+	}
+	mean := d.mean()
+	sum := 0.0
+	for i, count := range d.Counts {
+		width := d.Edges[i+1] - d.Edges[i]
+		mid := (d.Edges[i] + d.Edges[i+1]) / 2
+		sum += count * ((mid-mean)*(mid-mean) + width*width/12)
+	}
+	return math.Sqrt(sum / total)
+}
+
+// масштабує гістограму відносно середнього до заданого σ
+func (d EmpiricalDistribution) scaledTo(stdDev float64) EmpiricalDistribution {
+	current := d.StdDev()
+	if current == 0 || stdDev <= 0 {
+		return d
+	}
+	factor := stdDev / current
+	mean := d.mean()
+	edges := make([]float64, len(d.Edges))
+	for i, edge := range d.Edges {
+		edges[i] = mean + (edge-mean)*factor
+	}
+	return EmpiricalDistribution{Edges: edges, Counts: d.Counts}
+}
+
+// опис розподілу похибки, за яким для кожного σ будується конкретний розподіл
+type DistributionSpec struct {
+	Kind     string    `json:"kind"` // normal, truncated, student, empirical
+	Bias     float64   `json:"bias"`
+	DOF      float64   `json:"dof"`
+	Capacity float64   `json:"capacity"` // встановлена потужність, обмежує усічений розподіл
+	Samples  []float64 `json:"samples"`
+	Bins     int       `json:"bins"`
+}
+
+// перевіряє параметри розподілу; для розподілу Стьюдента з ν ≤ 2 σ нескінченне
+func (s DistributionSpec) validate() error {
+	if s.Kind == "student" && s.DOF > 0 && s.DOF <= 2 {
+		return errors.New("число ступенів свободи розподілу Стьюдента має бути більшим за 2")
+	}
+	return nil
+}
+
+// будує розподіл похибки для прогнозу power із заданим стандартним відхиленням
+func (s DistributionSpec) build(power, stdDev float64) Distribution {
+	switch s.Kind {
+	case "truncated":
+		// генерація не може бути меншою за нуль і більшою за встановлену потужність
+		upper := math.Inf(1)
+		if s.Capacity > power {
+			upper = s.Capacity - power
+		}
+		return TruncatedNormalDistribution{Mean: s.Bias, Sigma: stdDev, Lower: -power, Upper: upper}
+	case "student":
+		dof := s.DOF
+		if dof <= 0 {
+			dof = 5
+		}
+		scale := stdDev * math.Sqrt((dof-2)/dof)
+		return StudentTDistribution{Location: s.Bias, Scale: scale, DOF: dof}
+	case "empirical":
+		if len(s.Samples) > 0 {
+			return newEmpiricalDistribution(s.Samples, s.Bins).scaledTo(stdDev)
+		}
+	}
+	return NormalDistribution{Mean: s.Bias, Sigma: stdDev}
+}
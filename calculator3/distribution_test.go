@@ -0,0 +1,84 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRegularizedIncompleteBeta(t *testing.T) {
+	tests := []struct {
+		a, b, x float64
+		want    float64
+	}{
+		{a: 2, b: 1, x: 0.3, want: 0.09},         // I_x(a, 1) = x^a
+		{a: 1, b: 3, x: 0.2, want: 1 - 0.512},    // I_x(1, b) = 1 - (1-x)^b
+		{a: 4.5, b: 4.5, x: 0.5, want: 0.5},      // симетрія
+		{a: 0.5, b: 0.5, x: 0.25, want: 1.0 / 3}, // 2/π·arcsin(√x)
+		{a: 3, b: 2, x: 0, want: 0},
+		{a: 3, b: 2, x: 1, want: 1},
+	}
+	for _, tt := range tests {
+		got := regularizedIncompleteBeta(tt.a, tt.b, tt.x)
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("I_%v(%v, %v) = %v, очікувалось %v", tt.x, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// опорні точки розподілу Стьюдента з таблиць квантилів
+func TestStudentTCDF(t *testing.T) {
+	tests := []struct {
+		dof, x float64
+		want   float64
+	}{
+		{dof: 1, x: 1, want: 0.75},
+		{dof: 1, x: -1, want: 0.25},
+		{dof: 2, x: 1, want: 0.5 + 1/(2*math.Sqrt(3))},
+		{dof: 5, x: 0, want: 0.5},
+		{dof: 5, x: 2.015048372669157, want: 0.95},
+		{dof: 10, x: 2.2281388519649385, want: 0.975},
+		{dof: 30, x: -2.45726095096, want: 0.01},
+	}
+	for _, tt := range tests {
+		d := StudentTDistribution{Scale: 1, DOF: tt.dof}
+		if got := d.CDF(tt.x); math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("ν=%v: F(%v) = %v, очікувалось %v", tt.dof, tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestDistributionSpecKeepsStdDev(t *testing.T) {
+	specs := []DistributionSpec{
+		{Kind: "normal"},
+		{Kind: "student", DOF: 5},
+		{Kind: "empirical", Samples: []float64{-2, -1, -1, 0, 0, 0, 1, 1, 2}, Bins: 5},
+	}
+	for _, spec := range specs {
+		if got := spec.build(5, 0.25).StdDev(); math.Abs(got-0.25) > 1e-9 {
+			t.Errorf("%s: σ = %v, очікувалось 0.25", spec.Kind, got)
+		}
+	}
+}
+
+// при ν ≤ 2 дисперсія розподілу Стьюдента нескінченна, тому такі параметри відкидаються
+func TestDistributionSpecRejectsStudentWithInfiniteVariance(t *testing.T) {
+	for _, dof := range []float64{0.5, 1, 2} {
+		if err := (DistributionSpec{Kind: "student", DOF: dof}).validate(); err == nil {
+			t.Errorf("ν=%v: очікувалась помилка", dof)
+		}
+	}
+	for _, spec := range []DistributionSpec{{Kind: "student"}, {Kind: "student", DOF: 2.5}, {Kind: "normal", DOF: 1}} {
+		if err := spec.validate(); err != nil {
+			t.Errorf("%s, ν=%v: несподівана помилка %v", spec.Kind, spec.DOF, err)
+		}
+	}
+
+	req := MonteCarloRequest{
+		PlantParams:  PlantParams{Power: 5, TolerancePercent: 5, Cost: 7, PenaltyPrice: 7},
+		Sigma:        0.25,
+		Distribution: DistributionSpec{Kind: "student", DOF: 2},
+	}
+	if _, err := runMonteCarlo(req); err == nil {
+		t.Error("Монте-Карло з ν=2: очікувалась помилка")
+	}
+}
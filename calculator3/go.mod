@@ -0,0 +1,3 @@
+module calculator3
+
+go 1.21
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// кількість точок сітки для пошуку інтервалу зі зміною знаку
+const goalSeekGridPoints = 400
+
+// вхідні дані для оберненого розрахунку
+type GoalSeekRequest struct {
+	PlantParams
+	Market       string           `json:"market"`
+	Sigma        float64          `json:"sigma"`
+	Distribution DistributionSpec `json:"distribution"`
+	Variable     string           `json:"variable"` // sigma, tolerance або price
+	Target       float64          `json:"target"`   // цільовий прибуток, грн/добу
+}
+
+// результат оберненого розрахунку зі службовою інформацією про збіжність
+type GoalSeekResult struct {
+	Variable   string  `json:"variable"`
+	Label      string  `json:"label"`
+	Target     float64 `json:"target"`
+	Solved     bool    `json:"solved"`
+	Solution   float64 `json:"solution"`
+	Profit     float64 `json:"profit"`
+	Residual   float64 `json:"residual"`
+	Iterations int     `json:"iterations"`
+	Lower      float64 `json:"lower"` // інтервал, у якому знайдено корінь
+	Upper      float64 `json:"upper"`
+	MinProfit  float64 `json:"minProfit"` // досяжний діапазон прибутку на області пошуку
+	MaxProfit  float64 `json:"maxProfit"`
+	Message    string  `json:"message"`
+}
+
+// дані сторінки оберненого розрахунку
+type GoalSeekPageData struct {
+	Profiles []MarketRules
+	Error    string
+	Result   *GoalSeekResult
+}
+
+// невідома величина, її область пошуку та вплив на параметри розрахунку
+type goalSeekVariable struct {
+	label    string
+	lower    float64
+	upper    float64
+	logScale bool
+	apply    func(params *PlantParams, sigma *float64, value float64)
+}
+
+// описує невідому величину для заданої назви
+func newGoalSeekVariable(name string, power float64, rules MarketRules, fixedPenalty bool) (goalSeekVariable, error) {
+	switch name {
+	case "sigma":
+		return goalSeekVariable{
+			label: "Відхилення σ", lower: power * 1e-6, upper: power * 100, logScale: true,
+			apply: func(_ *PlantParams, sigma *float64, value float64) { *sigma = value },
+		}, nil
+	case "tolerance":
+		return goalSeekVariable{
+			label: "Допустимий коридор відхилень, %", lower: 0, upper: 100,
+			apply: func(params *PlantParams, _ *float64, value float64) { params.TolerancePercent = value },
+		}, nil
+	case "price":
+		return goalSeekVariable{
+			label: "Вартість, грн/кВт·год", lower: 1e-6, upper: 1e6, logScale: true,
+			apply: func(params *PlantParams, _ *float64, value float64) {
+				params.Cost = value
+				if !fixedPenalty {
+					params.PenaltyPrice = value * rules.PenaltyRatio
+				}
+			},
+		}, nil
+	}
+	return goalSeekVariable{}, fmt.Errorf("невідома змінна %q: очікується sigma, tolerance або price", name)
+}
+
+// добовий прибуток без округлення, щоб функція для пошуку кореня була гладкою
+func dailyProfit(params PlantParams, errors Distribution) float64 {
+	share := shareWithinBand(params, errors)
+	return params.Power * 24 * (share*params.Cost - (1-share)*params.PenaltyPrice)
+}
+
+// метод Брента для кореня f на інтервалі [a, b] зі зміною знаку
+func brentRoot(f func(float64) float64, a, b, tolerance float64, maxIterations int) (float64, int, bool) {
+	fa, fb := f(a), f(b)
+	if fa == 0 {
+		return a, 0, true
+	}
+	if fb == 0 {
+		return b, 0, true
+	}
+	c, fc := a, fa
+	d := b - a
+	e := d
+	for iteration := 1; iteration <= maxIterations; iteration++ {
+		if (fb > 0) == (fc > 0) {
+			c, fc = a, fa
+			d = b - a
+			e = d
+		}
+		if math.Abs(fc) < math.Abs(fb) {
+			a, b, c = b, c, b
+			fa, fb, fc = fb, fc, fb
+		}
+		tol := 2*math.SmallestNonzeroFloat64 + 0.5*tolerance*math.Max(math.Abs(b), 1)
+		middle := 0.5 * (c - b)
+		if math.Abs(middle) <= tol || fb == 0 {
+			return b, iteration, true
+		}
+		if math.Abs(e) >= tol && math.Abs(fa) > math.Abs(fb) {
+			// обернена квадратична інтерполяція або метод січних
+			var p, q float64
+			s := fb / fa
+			if a == c {
+				p = 2 * middle * s
+				q = 1 - s
+			} else {
+				q = fa / fc
+				r := fb / fc
+				p = s * (2*middle*q*(q-r) - (b-a)*(r-1))
+				q = (q - 1) * (r - 1) * (s - 1)
+			}
+			if p > 0 {
+				q = -q
+			}
+			p = math.Abs(p)
+			if 2*p < math.Min(3*middle*q-math.Abs(tol*q), math.Abs(e*q)) {
+				e = d
+				d = p / q
+			} else {
+				d = middle
+				e = d
+			}
+		} else {
+			d = middle
+			e = d
+		}
+		a, fa = b, fb
+		if math.Abs(d) > tol {
+			b += d
+		} else {
+			b += math.Copysign(tol, middle)
+		}
+		fb = f(b)
+	}
+	return b, maxIterations, false
+}
+
+// знаходить значення змінної, за якого прибуток дорівнює цільовому
+func solveGoalSeek(req GoalSeekRequest) (GoalSeekResult, error) {
+	if req.Power <= 0 {
+		return GoalSeekResult{}, errors.New("потужність має бути додатною")
+	}
+	if err := req.Distribution.validate(); err != nil {
+		return GoalSeekResult{}, err
+	}
+	rules := findMarketRules(req.Market)
+	fixedPenalty := req.PenaltyPrice > 0
+	params := req.PlantParams.withMarketDefaults(rules)
+
+	variable, err := newGoalSeekVariable(req.Variable, params.Power, rules, fixedPenalty)
+	if err != nil {
+		return GoalSeekResult{}, err
+	}
+	if req.Variable != "sigma" && req.Sigma <= 0 {
+		return GoalSeekResult{}, errors.New("відхилення σ має бути додатним")
+	}
+
+	profitAt := func(value float64) float64 {
+		p, sigma := params, req.Sigma
+		variable.apply(&p, &sigma, value)
+		return dailyProfit(p, req.Distribution.build(p.Power, sigma))
+	}
+	objective := func(value float64) float64 { return profitAt(value) - req.Target }
+
+	result := GoalSeekResult{
+		Variable:  req.Variable,
+		Label:     variable.label,
+		Target:    req.Target,
+		MinProfit: math.Inf(1),
+		MaxProfit: math.Inf(-1),
+	}
+
+	// прохід сіткою: межі досяжного прибутку та перший інтервал зі зміною знаку
+	previous, previousValue := 0.0, 0.0
+	bracketFound := false
+	for i := 0; i <= goalSeekGridPoints; i++ {
+		fraction := float64(i) / goalSeekGridPoints
+		value := variable.lower + (variable.upper-variable.lower)*fraction
+		if variable.logScale {
+			value = variable.lower * math.Pow(variable.upper/variable.lower, fraction)
+		}
+		current := objective(value)
+		result.MinProfit = math.Min(result.MinProfit, current+req.Target)
+		result.MaxProfit = math.Max(result.MaxProfit, current+req.Target)
+		if i > 0 && !bracketFound && (current == 0 || (current > 0) != (previous > 0)) {
+			result.Lower, result.Upper = previousValue, value
+			bracketFound = true
+		}
+		previous, previousValue = current, value
+	}
+	result.MinProfit = roundTwo(result.MinProfit)
+	result.MaxProfit = roundTwo(result.MaxProfit)
+
+	if !bracketFound {
+		result.Message = fmt.Sprintf(
+			"Розв'язку не існує: на області %.4g – %.4g прибуток змінюється від %.2f до %.2f грн, цільове значення %.2f грн недосяжне.",
+			variable.lower, variable.upper, result.MinProfit, result.MaxProfit, req.Target)
+		return result, nil
+	}
+
+	solution, iterations, converged := brentRoot(objective, result.Lower, result.Upper, 1e-12, 200)
+	result.Solved = converged
+	result.Solution = solution
+	result.Iterations = iterations
+	result.Profit = roundTwo(profitAt(solution))
+	result.Residual = objective(solution)
+	if converged {
+		result.Message = fmt.Sprintf("Збіжність досягнута за %d ітерацій.", iterations)
+	} else {
+		result.Message = fmt.Sprintf("Метод не зійшовся за %d ітерацій; наведено останнє наближення.", iterations)
+	}
+	return result, nil
+}
+
+// Обробник сторінки оберненого розрахунку
+func handleGoalSeek(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := template.ParseFiles("goalseek.html")
+	if err != nil {
+		http.Error(w, "Не вдалося завантажити сторінку", http.StatusInternalServerError)
+		return
+	}
+
+	data := GoalSeekPageData{Profiles: marketProfiles}
+
+	if r.Method == http.MethodPost {
+		r.ParseForm()
+		req := GoalSeekRequest{
+			Market:       r.FormValue("market"),
+			Distribution: parseDistributionSpec(r),
+			Variable:     r.FormValue("variable"),
+		}
+		req.Power, _ = strconv.ParseFloat(r.FormValue("power"), 64)
+		req.Cost, _ = strconv.ParseFloat(r.FormValue("cost"), 64)
+		req.TolerancePercent, _ = strconv.ParseFloat(r.FormValue("tolerancePercent"), 64)
+		req.PenaltyPrice, _ = strconv.ParseFloat(r.FormValue("penaltyPrice"), 64)
+		req.Sigma, _ = strconv.ParseFloat(r.FormValue("sigma"), 64)
+		req.Target, _ = strconv.ParseFloat(r.FormValue("target"), 64)
+
+		result, err := solveGoalSeek(req)
+		if err != nil {
+			data.Error = err.Error()
+		} else {
+			data.Result = &result
+		}
+	}
+	tmpl.Execute(w, data)
+}
+
+// Обробник API оберненого розрахунку
+func handleGoalSeekAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req GoalSeekRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := solveGoalSeek(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBrentRoot(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(float64) float64
+		a, b float64
+		root float64
+	}{
+		{"x³-2x-5", func(x float64) float64 { return x*x*x - 2*x - 5 }, 2, 3, 2.0945514815423265},
+		{"cos x - x", func(x float64) float64 { return math.Cos(x) - x }, 0, 1, 0.7390851332151607},
+		{"x²-2", func(x float64) float64 { return x*x - 2 }, 0, 2, math.Sqrt2},
+		{"корінь на межі", func(x float64) float64 { return x - 1 }, 1, 5, 1},
+	}
+	for _, tt := range tests {
+		root, iterations, ok := brentRoot(tt.f, tt.a, tt.b, 1e-12, 100)
+		if !ok {
+			t.Errorf("%s: не зійшовся за %d ітерацій", tt.name, iterations)
+			continue
+		}
+		if math.Abs(root-tt.root) > 1e-10 {
+			t.Errorf("%s: корінь %v, очікувалось %v", tt.name, root, tt.root)
+		}
+	}
+}
+
+func TestSolveGoalSeekBreakEvenSigma(t *testing.T) {
+	req := GoalSeekRequest{
+		PlantParams: PlantParams{Power: 5, TolerancePercent: 5, Cost: 7, PenaltyPrice: 7},
+		Variable:    "sigma",
+	}
+	result, err := solveGoalSeek(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Solved {
+		t.Fatalf("розв'язок не знайдено: %s", result.Message)
+	}
+	// нульовий прибуток за рівних цін — половина енергії в коридорі: 0.25 = 0.6745·σ
+	want := 0.25 / 0.6744897501960817
+	if math.Abs(result.Solution-want) > 1e-3 {
+		t.Errorf("σ беззбитковості %v, очікувалось %v", result.Solution, want)
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// максимальний розмір завантажуваного файлу з історією прогнозів
+const maxHistorySize = 32 << 20
+
+// погодинний запис прогнозу та фактичної генерації
+type ForecastRecord struct {
+	Time     string  `json:"time"`
+	Forecast float64 `json:"forecast"`
+	Actual   float64 `json:"actual"`
+}
+
+// значення похибки для заданого перцентиля
+type Percentile struct {
+	Level float64 `json:"level"`
+	Value float64 `json:"value"`
+}
+
+// статистика похибок прогнозу та підібраний нормальний розподіл
+type ErrorStatistics struct {
+	Count       int          `json:"count"`
+	Bias        float64      `json:"bias"`
+	MAE         float64      `json:"mae"`
+	RMSE        float64      `json:"rmse"`
+	StdDev      float64      `json:"stdDev"`
+	Percentiles []Percentile `json:"percentiles"`
+	Errors      []float64    `json:"-"`
+
+	// неокруглені зсув і σ для підбору розподілу
+	mean  float64
+	sigma float64
+}
+
+// рівні перцентилів, що виводяться у звіті
+var percentileLevels = []float64{5, 10, 25, 50, 75, 90, 95}
+
+// знаходить номер стовпця, назва якого містить одне з ключових слів
+func findColumn(header []string, keywords ...string) int {
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for _, keyword := range keywords {
+			if strings.Contains(name, keyword) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// зчитує CSV з прогнозом і фактом; роздільник «,» або «;»
+func parseForecastCSV(r io.Reader) ([]ForecastRecord, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	firstLine, _, _ := bytes.Cut(content, []byte("\n"))
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("файл не містить даних")
+	}
+
+	// без заголовка вважаємо, що прогноз і факт — два останні стовпці
+	forecastColumn := findColumn(rows[0], "forecast", "прогноз")
+	actualColumn := findColumn(rows[0], "actual", "факт")
+	if forecastColumn < 0 || actualColumn < 0 {
+		forecastColumn, actualColumn = len(rows[0])-2, len(rows[0])-1
+	}
+	if forecastColumn < 0 {
+		return nil, errors.New("потрібні стовпці прогнозу та фактичної генерації")
+	}
+
+	var records []ForecastRecord
+	for _, row := range rows {
+		if len(row) <= forecastColumn || len(row) <= actualColumn {
+			continue
+		}
+		forecast, err1 := strconv.ParseFloat(strings.TrimSpace(row[forecastColumn]), 64)
+		actual, err2 := strconv.ParseFloat(strings.TrimSpace(row[actualColumn]), 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		record := ForecastRecord{Forecast: forecast, Actual: actual}
+		if forecastColumn > 0 && actualColumn > 0 {
+			record.Time = row[0]
+		}
+		records = append(records, record)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("не знайдено жодного рядка з числовими значеннями")
+	}
+	return records, nil
+}
+
+// перцентиль відсортованої вибірки з лінійною інтерполяцією
+func percentile(sorted []float64, level float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	position := level / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	fraction := position - float64(lower)
+	return sorted[lower] + (sorted[upper]-sorted[lower])*fraction
+}
+
+// розраховує статистику похибок (факт мінус прогноз)
+func calculateErrorStatistics(records []ForecastRecord) ErrorStatistics {
+	stats := ErrorStatistics{Count: len(records)}
+	if len(records) == 0 {
+		return stats
+	}
+
+	errorValues := make([]float64, len(records))
+	sum, absSum, squareSum := 0.0, 0.0, 0.0
+	for i, record := range records {
+		e := record.Actual - record.Forecast
+		errorValues[i] = e
+		sum += e
+		absSum += math.Abs(e)
+		squareSum += e * e
+	}
+	n := float64(len(records))
+	bias := sum / n
+
+	variance := 0.0
+	for _, e := range errorValues {
+		variance += (e - bias) * (e - bias)
+	}
+	if len(records) > 1 {
+		variance /= n - 1
+	}
+
+	sorted := append([]float64(nil), errorValues...)
+	sort.Float64s(sorted)
+	for _, level := range percentileLevels {
+		stats.Percentiles = append(stats.Percentiles, Percentile{Level: level, Value: roundTwo(percentile(sorted, level))})
+	}
+
+	stats.Bias = roundTwo(bias)
+	stats.MAE = roundTwo(absSum / n)
+	stats.RMSE = roundTwo(math.Sqrt(squareSum / n))
+	stats.StdDev = roundTwo(math.Sqrt(variance))
+	stats.Errors = errorValues
+	stats.mean = bias
+	stats.sigma = math.Sqrt(variance)
+	return stats
+}
+
+// підбирає параметри нормального розподілу похибки за неокругленою статистикою
+func (s ErrorStatistics) fitNormal() NormalDistribution {
+	return NormalDistribution{Mean: s.mean, Sigma: s.sigma}
+}
+
+// зчитує файл історії прогнозів з форми, якщо його завантажено
+func parseHistoryUpload(r *http.Request) (*ErrorStatistics, error) {
+	file, _, err := r.FormFile("history")
+	if err == http.ErrMissingFile || err == http.ErrNotMultipart {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := parseForecastCSV(file)
+	if err != nil {
+		return nil, err
+	}
+	stats := calculateErrorStatistics(records)
+	return &stats, nil
+}
+
+// Обробник, що повертає статистику похибок для CSV у тілі запиту
+func handleHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	records, err := parseForecastCSV(http.MaxBytesReader(w, r.Body, maxHistorySize))
+	if err != nil {
+		http.Error(w, "Invalid CSV: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stats := calculateErrorStatistics(records)
+	fitted := stats.fitNormal()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		ErrorStatistics
+		Fitted NormalDistribution `json:"fitted"`
+	}{stats, NormalDistribution{Mean: roundTwo(fitted.Mean), Sigma: roundTwo(fitted.Sigma)}})
+}
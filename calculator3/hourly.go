@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+)
+
+// вхідні дані погодинної симуляції ринку «на добу наперед»
+type HourlyRequest struct {
+	Forecast         []float64        `json:"forecast"`     // прогноз генерації на кожну годину
+	Sigma            []float64        `json:"sigma"`        // одне значення або по одному на годину
+	Price            []float64        `json:"price"`        // одне значення або по одному на годину
+	PenaltyPrice     []float64        `json:"penaltyPrice"` // необов'язково; 0 — за профілем ринку
+	Market           string           `json:"market"`
+	TolerancePercent float64          `json:"tolerancePercent"` // 0 — за профілем ринку
+	Distribution     DistributionSpec `json:"distribution"`
+}
+
+// результат розрахунку для однієї години
+type HourResult struct {
+	Hour             int     `json:"hour"`
+	Forecast         float64 `json:"forecast"`
+	Sigma            float64 `json:"sigma"`
+	Price            float64 `json:"price"`
+	PenaltyPrice     float64 `json:"penaltyPrice"`
+	ShareNoImbalance float64 `json:"shareNoImbalance"`
+	Revenue          float64 `json:"revenue"`
+	Penalty          float64 `json:"penalty"`
+	Profit           float64 `json:"profit"`
+}
+
+// погодинні та сумарні результати симуляції
+type HourlyResult struct {
+	Market       string       `json:"market"`
+	Hours        []HourResult `json:"hours"`
+	TotalEnergy  float64      `json:"totalEnergy"`
+	TotalRevenue float64      `json:"totalRevenue"`
+	TotalPenalty float64      `json:"totalPenalty"`
+	TotalProfit  float64      `json:"totalProfit"`
+}
+
+// дані сторінки погодинної симуляції
+type HourlyPageData struct {
+	Profiles []MarketRules
+	Error    string
+	Result   *HourlyResult
+}
+
+// значення ряду для години: одне спільне або окреме для кожної години
+func seriesValue(series []float64, hour int) float64 {
+	if len(series) == 1 {
+		return series[0]
+	}
+	return series[hour]
+}
+
+// перевіряє, що довжини рядів узгоджені з прогнозом
+func (req HourlyRequest) validate() error {
+	hours := len(req.Forecast)
+	if hours == 0 {
+		return errors.New("потрібен погодинний прогноз генерації")
+	}
+	if len(req.Sigma) != 1 && len(req.Sigma) != hours {
+		return errors.New("кількість значень σ має бути 1 або дорівнювати кількості годин")
+	}
+	if len(req.Price) != 1 && len(req.Price) != hours {
+		return errors.New("кількість цін має бути 1 або дорівнювати кількості годин")
+	}
+	if len(req.PenaltyPrice) > 1 && len(req.PenaltyPrice) != hours {
+		return errors.New("кількість цін небалансу має бути 0, 1 або дорівнювати кількості годин")
+	}
+	return req.Distribution.validate()
+}
+
+// розраховує погодинну виручку, штрафи та прибуток
+func simulateHourly(req HourlyRequest) (HourlyResult, error) {
+	if err := req.validate(); err != nil {
+		return HourlyResult{}, err
+	}
+	rules := findMarketRules(req.Market)
+
+	result := HourlyResult{Market: rules.Title, Hours: make([]HourResult, len(req.Forecast))}
+	for hour, forecast := range req.Forecast {
+		params := PlantParams{
+			Power:            forecast,
+			TolerancePercent: req.TolerancePercent,
+			Cost:             seriesValue(req.Price, hour),
+		}
+		if len(req.PenaltyPrice) > 0 {
+			params.PenaltyPrice = seriesValue(req.PenaltyPrice, hour)
+		}
+		params = params.withMarketDefaults(rules)
+		sigma := seriesValue(req.Sigma, hour)
+
+		share := 1.0
+		if forecast > 0 {
+			share = shareWithinBand(params, req.Distribution.build(forecast, sigma))
+		}
+		revenue := forecast * share * params.Cost
+		penalty := forecast * (1 - share) * params.PenaltyPrice
+
+		result.Hours[hour] = HourResult{
+			Hour:             hour + 1,
+			Forecast:         forecast,
+			Sigma:            sigma,
+			Price:            params.Cost,
+			PenaltyPrice:     params.PenaltyPrice,
+			ShareNoImbalance: roundTwo(share * 100),
+			Revenue:          roundTwo(revenue),
+			Penalty:          roundTwo(penalty),
+			Profit:           roundTwo(revenue - penalty),
+		}
+		result.TotalEnergy += forecast
+		result.TotalRevenue += revenue
+		result.TotalPenalty += penalty
+	}
+
+	result.TotalEnergy = roundTwo(result.TotalEnergy)
+	result.TotalProfit = roundTwo(result.TotalRevenue - result.TotalPenalty)
+	result.TotalRevenue = roundTwo(result.TotalRevenue)
+	result.TotalPenalty = roundTwo(result.TotalPenalty)
+	return result, nil
+}
+
+// Обробник сторінки погодинної симуляції
+func handleHourly(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := template.ParseFiles("hourly.html")
+	if err != nil {
+		http.Error(w, "Не вдалося завантажити сторінку", http.StatusInternalServerError)
+		return
+	}
+
+	data := HourlyPageData{Profiles: marketProfiles}
+
+	if r.Method == http.MethodPost {
+		r.ParseForm()
+		req := HourlyRequest{
+			Forecast:     parseFloatList(r.FormValue("forecast")),
+			Sigma:        parseFloatList(r.FormValue("sigma")),
+			Price:        parseFloatList(r.FormValue("price")),
+			PenaltyPrice: parseFloatList(r.FormValue("penaltyPrice")),
+			Market:       r.FormValue("market"),
+			Distribution: parseDistributionSpec(r),
+		}
+		req.TolerancePercent, _ = strconv.ParseFloat(r.FormValue("tolerancePercent"), 64)
+
+		result, err := simulateHourly(req)
+		if err != nil {
+			data.Error = err.Error()
+		} else {
+			data.Result = &result
+		}
+	}
+	tmpl.Execute(w, data)
+}
+
+// Обробник API погодинної симуляції
+func handleHourlyAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req HourlyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := simulateHourly(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
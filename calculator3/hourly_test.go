@@ -0,0 +1,40 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// для рівного профілю сума погодинних прибутків дорівнює добовому аналітичному розрахунку
+func TestSimulateHourlyFlatProfileMatchesDailyResult(t *testing.T) {
+	params := PlantParams{Power: 5, TolerancePercent: 5, Cost: 7, PenaltyPrice: 7}
+	forecast := make([]float64, 24)
+	for hour := range forecast {
+		forecast[hour] = params.Power
+	}
+	for _, sigma := range []float64{0.25, 1} {
+		req := HourlyRequest{
+			Forecast:         forecast,
+			Sigma:            []float64{sigma},
+			Price:            []float64{params.Cost},
+			PenaltyPrice:     []float64{params.PenaltyPrice},
+			TolerancePercent: params.TolerancePercent,
+		}
+		result, err := simulateHourly(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		daily := calculateScenario(params, NormalDistribution{Sigma: sigma})
+		if math.Abs(result.TotalProfit-daily.Profit) > 0.011 {
+			t.Errorf("σ=%v: погодинний прибуток %v, очікувалось %v", sigma, result.TotalProfit, daily.Profit)
+		}
+		if math.Abs(result.TotalPenalty-daily.Penalty) > 0.011 || result.TotalEnergy != 120 {
+			t.Errorf("σ=%v: штраф %v і енергія %v, очікувалось %v і 120", sigma, result.TotalPenalty, result.TotalEnergy, daily.Penalty)
+		}
+		for _, hour := range result.Hours {
+			if hour.ShareNoImbalance != daily.ShareNoImbalance {
+				t.Errorf("σ=%v, година %d: частка %v, очікувалось %v", sigma, hour.Hour, hour.ShareNoImbalance, daily.ShareNoImbalance)
+			}
+		}
+	}
+}
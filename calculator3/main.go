@@ -1,35 +1,134 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"html/template"
 	"math"
 	"net/http"
 	"strconv"
-	"html/template"
+	"strings"
+	"unicode"
 )
 
-// обчислює ймовірність нормального розподілу
-func normalPDF(x, mean, stdDev float64) float64 {
-	return (1 / (stdDev * math.Sqrt(2*math.Pi))) *
-		math.Exp(-0.5*math.Pow((x-mean)/stdDev, 2))
+// обчислює ймовірність нормального розподілу у заданому діапазоні
+func calculateNormalProbability(mean, stdDev, lower, upper float64) float64 {
+	return probabilityBetween(NormalDistribution{Mean: mean, Sigma: stdDev}, lower, upper)
 }
 
-// обчислює ймовірність у заданому діапазоні
-func calculateNormalProbability(mean, stdDev, lower, upper float64) float64 {
-	steps := 1000
-	stepSize := (upper - lower) / float64(steps)
-	area := 0.0
-	for i := 0; i < steps; i++ {
-		x1 := lower + float64(i)*stepSize
-		x2 := x1 + stepSize
-		area += (normalPDF(x1, mean, stdDev) + normalPDF(x2, mean, stdDev)) / 2 * stepSize
-	}
-	return area
+// результат розрахунку для одного значення похибки прогнозу
+type ScenarioResult struct {
+	Distribution      string  `json:"distribution"`
+	Deviation         float64 `json:"deviation"`
+	ShareNoImbalance  float64 `json:"shareNoImbalance"`
+	EnergyNoImbalance float64 `json:"energyNoImbalance"`
+	EnergyImbalance   float64 `json:"energyImbalance"`
+	Revenue           float64 `json:"revenue"`
+	Penalty           float64 `json:"penalty"`
+	Profit            float64 `json:"profit"`
 }
 
 // структура для передавання результату в шаблон
 type CalculationResult struct {
-	ProfitImproved float64
+	Market       string           `json:"market"`
+	LowerBound   float64          `json:"lowerBound"`
+	UpperBound   float64          `json:"upperBound"`
+	PenaltyPrice float64          `json:"penaltyPrice"`
+	Initial      ScenarioResult   `json:"initial"`
+	Improved     ScenarioResult   `json:"improved"`
+	ProfitGain   float64          `json:"profitGain"`
+	History      *ErrorStatistics `json:"history,omitempty"`
+}
+
+// дані сторінки: перелік ринкових профілів і результат
+type PageData struct {
+	Profiles []MarketRules
+	Result   *CalculationResult
+	Error    string
+}
+
+// округлення до 2 знаків після коми
+func roundTwo(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// частка енергії, що потрапляє в коридор без небалансу
+func shareWithinBand(params PlantParams, errors Distribution) float64 {
+	lower, upper := params.band()
+	return probabilityBetween(errors, lower-params.Power, upper-params.Power)
+}
+
+// розраховує частку енергії без небалансу, виручку, штраф і прибуток
+func calculateScenario(params PlantParams, errors Distribution) ScenarioResult {
+	share := shareWithinBand(params, errors)
+
+	energyNoImbalance := params.Power * 24 * share
+	energyImbalance := params.Power * 24 * (1 - share)
+
+	revenue := energyNoImbalance * params.Cost
+	penalty := energyImbalance * params.PenaltyPrice
+
+	return ScenarioResult{
+		Distribution:      errors.Name(),
+		Deviation:         roundTwo(errors.StdDev()),
+		ShareNoImbalance:  roundTwo(share * 100),
+		EnergyNoImbalance: roundTwo(energyNoImbalance),
+		EnergyImbalance:   roundTwo(energyImbalance),
+		Revenue:           roundTwo(revenue),
+		Penalty:           roundTwo(penalty),
+		Profit:            roundTwo(revenue - penalty),
+	}
+}
+
+// порівнює прибуток до та після вдосконалення прогнозу
+func calculateProfit(params PlantParams, spec DistributionSpec, deviationInitial, deviationImproved float64) CalculationResult {
+	initial := calculateScenario(params, spec.build(params.Power, deviationInitial))
+	improved := calculateScenario(params, spec.build(params.Power, deviationImproved))
+	lower, upper := params.band()
+
+	return CalculationResult{
+		LowerBound:   roundTwo(lower),
+		UpperBound:   roundTwo(upper),
+		PenaltyPrice: params.PenaltyPrice,
+		Initial:      initial,
+		Improved:     improved,
+		ProfitGain:   roundTwo(improved.Profit - initial.Profit),
+	}
+}
+
+// зчитує параметри станції з форми; незадані або нульові коридор і ціна небалансу
+// беруться з ринкового профілю
+func parsePlantParams(r *http.Request, rules MarketRules) PlantParams {
+	var params PlantParams
+	params.Power, _ = strconv.ParseFloat(r.FormValue("power"), 64)
+	params.Cost, _ = strconv.ParseFloat(r.FormValue("cost"), 64)
+	params.TolerancePercent, _ = strconv.ParseFloat(r.FormValue("tolerancePercent"), 64)
+	params.PenaltyPrice, _ = strconv.ParseFloat(r.FormValue("penaltyPrice"), 64)
+	return params.withMarketDefaults(rules)
+}
+
+// зчитує з форми вид розподілу похибки та його параметри
+func parseDistributionSpec(r *http.Request) DistributionSpec {
+	spec := DistributionSpec{Kind: r.FormValue("distribution")}
+	spec.Bias, _ = strconv.ParseFloat(r.FormValue("bias"), 64)
+	spec.DOF, _ = strconv.ParseFloat(r.FormValue("dof"), 64)
+	spec.Capacity, _ = strconv.ParseFloat(r.FormValue("capacity"), 64)
+	spec.Bins, _ = strconv.Atoi(r.FormValue("bins"))
+	spec.Samples = parseFloatList(r.FormValue("errorSamples"))
+	return spec
+}
+
+// розбирає список чисел, розділених комами, крапками з комою або пробілами
+func parseFloatList(text string) []float64 {
+	var values []float64
+	for _, field := range strings.FieldsFunc(text, func(c rune) bool {
+		return c == ',' || c == ';' || unicode.IsSpace(c)
+	}) {
+		if value, err := strconv.ParseFloat(field, 64); err == nil {
+			values = append(values, value)
+		}
+	}
+	return values
 }
 
 // Обробник для головної сторінки
@@ -40,29 +139,65 @@ func handleIndex(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	data := PageData{Profiles: marketProfiles}
+
 	if r.Method == http.MethodPost {
-		r.ParseForm()
-		power, _ := strconv.ParseFloat(r.FormValue("power"), 64)
+		r.ParseMultipartForm(maxHistorySize)
+		rules := findMarketRules(r.FormValue("market"))
+		params := parsePlantParams(r, rules)
+		spec := parseDistributionSpec(r)
+		deviationInitial, _ := strconv.ParseFloat(r.FormValue("deviationInitial"), 64)
 		deviationImproved, _ := strconv.ParseFloat(r.FormValue("deviationImproved"), 64)
-		cost, _ := strconv.ParseFloat(r.FormValue("cost"), 64)
-
-		probabilityNoImbalance2 := calculateNormalProbability(5.0, deviationImproved, 4.75, 5.25)
 
-		w2NoImbalance := power * 24 * probabilityNoImbalance2
-		w2Imbalance := power * 24 * (1 - probabilityNoImbalance2)
+		// параметри, підібрані за історією прогнозів, замінюють введене вручну відхилення
+		history, err := parseHistoryUpload(r)
+		if err != nil {
+			http.Error(w, "Не вдалося прочитати файл історії: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if history != nil {
+			fitted := history.fitNormal()
+			deviationInitial = fitted.Sigma
+			spec.Bias = fitted.Mean
+			if spec.Kind == "empirical" && len(spec.Samples) == 0 {
+				spec.Samples = history.Errors
+			}
+		}
 
-		profitImproved := math.Round(((w2NoImbalance * cost) - (w2Imbalance * cost)) * 100) / 100
-
-		result := CalculationResult{ProfitImproved: profitImproved}
-		tmpl.Execute(w, result)
-	} else {
-		tmpl.Execute(w, nil)
+		if err := spec.validate(); err != nil {
+			data.Error = err.Error()
+		} else {
+			result := calculateProfit(params, spec, deviationInitial, deviationImproved)
+			result.Market = rules.Title
+			result.History = history
+			data.Result = &result
+		}
 	}
+	tmpl.Execute(w, data)
+}
+
+// Обробник, що повертає доступні ринкові профілі
+func handleMarkets(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(marketProfiles)
 }
 
 func main() {
 	http.HandleFunc("/", handleIndex)
+	http.HandleFunc("/api/markets", handleMarkets)
+	http.HandleFunc("/api/history", handleHistory)
+	http.HandleFunc("/hourly", handleHourly)
+	http.HandleFunc("/api/hourly", handleHourlyAPI)
+	http.HandleFunc("/montecarlo", handleMonteCarlo)
+	http.HandleFunc("/api/montecarlo", handleMonteCarloAPI)
+	http.HandleFunc("/goalseek", handleGoalSeek)
+	http.HandleFunc("/api/goalseek", handleGoalSeekAPI)
+	http.HandleFunc("/battery", handleBattery)
+	http.HandleFunc("/api/battery", handleBatteryAPI)
+	http.HandleFunc("/wind", handleWind)
+	http.HandleFunc("/api/wind", handleWindAPI)
+	http.HandleFunc("/portfolio", handlePortfolio)
+	http.HandleFunc("/api/portfolio", handlePortfolioAPI)
 	fmt.Println("Сервер запущено на http://localhost:8080")
 	http.ListenAndServe(":8080", nil)
 }
-
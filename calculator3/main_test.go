@@ -0,0 +1,35 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// значення з початкової версії калькулятора (потужність 5 МВт, ціна 7, коридор 5 %)
+func TestCalculateScenarioMatchesBaseline(t *testing.T) {
+	params := PlantParams{Power: 5, TolerancePercent: 5, Cost: 7, PenaltyPrice: 7}
+	tests := []struct {
+		sigma  float64
+		share  float64
+		profit float64
+	}{
+		{sigma: 0.25, share: 68.27, profit: 306.92},
+		{sigma: 1, share: 19.74, profit: -508.35},
+	}
+	for _, tt := range tests {
+		result := calculateScenario(params, NormalDistribution{Sigma: tt.sigma})
+		if result.ShareNoImbalance != tt.share {
+			t.Errorf("σ=%v: частка без небалансу %v, очікувалось %v", tt.sigma, result.ShareNoImbalance, tt.share)
+		}
+		if math.Abs(result.Profit-tt.profit) > 0.011 {
+			t.Errorf("σ=%v: прибуток %v, очікувалось %v", tt.sigma, result.Profit, tt.profit)
+		}
+	}
+}
+
+func TestCalculateProfitComparesForecasts(t *testing.T) {
+	result := calculateProfit(PlantParams{Power: 5, TolerancePercent: 5, Cost: 7, PenaltyPrice: 7}, DistributionSpec{Kind: "normal"}, 1, 0.25)
+	if math.Abs(result.ProfitGain-815.27) > 0.011 {
+		t.Errorf("приріст прибутку %v, очікувалось 815.27", result.ProfitGain)
+	}
+}
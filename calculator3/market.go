@@ -0,0 +1,51 @@
+package main
+
+// правила балансуючого ринку: допустимий коридор відхилень і ціна небалансу
+type MarketRules struct {
+	Name             string  `json:"name"`
+	Title            string  `json:"title"`
+	TolerancePercent float64 `json:"tolerancePercent"`
+	PenaltyRatio     float64 `json:"penaltyRatio"` // ціна небалансу відносно ціни продажу
+}
+
+// профілі ринкових правил, з яких обирається контракт для станції
+var marketProfiles = []MarketRules{
+	{Name: "dam", Title: "Ринок «на добу наперед», коридор 5 %", TolerancePercent: 5, PenaltyRatio: 1},
+	{Name: "strict", Title: "Жорсткий баланс, коридор 2.5 %", TolerancePercent: 2.5, PenaltyRatio: 1.2},
+	{Name: "intraday", Title: "Внутрішньодобовий ринок, коридор 10 %", TolerancePercent: 10, PenaltyRatio: 1.5},
+}
+
+// повертає профіль за назвою; за замовчуванням перший
+func findMarketRules(name string) MarketRules {
+	for _, rules := range marketProfiles {
+		if rules.Name == name {
+			return rules
+		}
+	}
+	return marketProfiles[0]
+}
+
+// параметри станції та контракту, за якими рахується прибуток
+type PlantParams struct {
+	Power            float64 `json:"power"`
+	TolerancePercent float64 `json:"tolerancePercent"`
+	Cost             float64 `json:"cost"`
+	PenaltyPrice     float64 `json:"penaltyPrice"`
+}
+
+// межі коридору без небалансу навколо середньої потужності
+func (p PlantParams) band() (float64, float64) {
+	delta := p.Power * p.TolerancePercent / 100
+	return p.Power - delta, p.Power + delta
+}
+
+// доповнює незадані коридор і ціну небалансу значеннями з ринкового профілю
+func (p PlantParams) withMarketDefaults(rules MarketRules) PlantParams {
+	if p.TolerancePercent <= 0 {
+		p.TolerancePercent = rules.TolerancePercent
+	}
+	if p.PenaltyPrice <= 0 {
+		p.PenaltyPrice = p.Cost * rules.PenaltyRatio
+	}
+	return p
+}
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"math"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// обмеження, щоб один запит не займав сервер надто довго
+const maxMonteCarloRuns = 10000
+
+// кількість точок таблиці оберненої функції розподілу
+const quantileTableSize = 4001
+
+// тривалість місяців невисокосного року в днях
+var monthDays = []int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+var monthNames = []string{
+	"Січень", "Лютий", "Березень", "Квітень", "Травень", "Червень",
+	"Липень", "Серпень", "Вересень", "Жовтень", "Листопад", "Грудень",
+}
+
+// вхідні дані для аналізу ризиків методом Монте-Карло
+type MonteCarloRequest struct {
+	PlantParams
+	Market          string           `json:"market"`
+	Sigma           float64          `json:"sigma"`
+	Distribution    DistributionSpec `json:"distribution"`
+	Runs            int              `json:"runs"`
+	Seed            int64            `json:"seed"`
+	Autocorrelation float64          `json:"autocorrelation"` // коефіцієнт AR(1) між сусідніми годинами
+	Confidence      float64          `json:"confidence"`      // рівень довіри для VaR, %
+	Bins            int              `json:"bins"`
+}
+
+// показники розподілу чистого доходу
+type RiskSummary struct {
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stdDev"`
+	P10    float64 `json:"p10"` // перевищується з імовірністю 10 %
+	P50    float64 `json:"p50"`
+	P90    float64 `json:"p90"` // перевищується з імовірністю 90 %
+	VaR    float64 `json:"var"`
+	CVaR   float64 `json:"cvar"`
+}
+
+// показники ризику для одного місяця
+type MonthRisk struct {
+	Month int    `json:"month"`
+	Name  string `json:"name"`
+	RiskSummary
+}
+
+// інтервал гістограми річного доходу
+type HistogramBin struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int     `json:"count"`
+	Share float64 `json:"share"` // частка реалізацій, %
+}
+
+// результати аналізу ризиків
+type MonteCarloResult struct {
+	Market     string         `json:"market"`
+	Runs       int            `json:"runs"`
+	Seed       int64          `json:"seed"`
+	Confidence float64        `json:"confidence"`
+	Expected   float64        `json:"expected"` // аналітичне очікуване значення за рік
+	Annual     RiskSummary    `json:"annual"`
+	Monthly    []MonthRisk    `json:"monthly"`
+	Histogram  []HistogramBin `json:"histogram"`
+}
+
+// дані сторінки аналізу ризиків
+type MonteCarloPageData struct {
+	Profiles []MarketRules
+	Error    string
+	Result   *MonteCarloResult
+}
+
+// знаходить квантиль розподілу бісекцією за функцією розподілу
+func quantile(d Distribution, p float64) float64 {
+	lower, upper := -1.0, 1.0
+	for i := 0; i < 200 && d.CDF(lower) > p; i++ {
+		lower *= 2
+	}
+	for i := 0; i < 200 && d.CDF(upper) < p; i++ {
+		upper *= 2
+	}
+	for i := 0; i < 100; i++ {
+		middle := (lower + upper) / 2
+		if d.CDF(middle) < p {
+			lower = middle
+		} else {
+			upper = middle
+		}
+	}
+	return (lower + upper) / 2
+}
+
+// перетворює стандартну нормальну величину на похибку із заданим розподілом
+func newErrorSampler(d Distribution) func(z float64) float64 {
+	if normal, ok := d.(NormalDistribution); ok {
+		return func(z float64) float64 { return normal.Mean + normal.Sigma*z }
+	}
+
+	// для інших розподілів — таблиця оберненої функції розподілу (гаусова копула)
+	table := make([]float64, quantileTableSize)
+	for i := range table {
+		p := (float64(i) + 0.5) / quantileTableSize
+		table[i] = quantile(d, p)
+	}
+	return func(z float64) float64 {
+		position := normalCDF(z, 0, 1)*quantileTableSize - 0.5
+		if position <= 0 {
+			return table[0]
+		}
+		if position >= quantileTableSize-1 {
+			return table[quantileTableSize-1]
+		}
+		index := int(position)
+		fraction := position - float64(index)
+		return table[index] + (table[index+1]-table[index])*fraction
+	}
+}
+
+// погодинні похибки прогнозу з автокореляцією AR(1) між сусідніми годинами
+type hourlyErrors struct {
+	rng             *rand.Rand
+	sample          func(z float64) float64
+	autocorrelation float64
+	innovationScale float64
+	z               float64
+}
+
+// створює генератор похибок із заданим розподілом і зерном
+func newHourlyErrors(d Distribution, autocorrelation float64, seed int64) *hourlyErrors {
+	return &hourlyErrors{
+		rng:             rand.New(rand.NewSource(seed)),
+		sample:          newErrorSampler(d),
+		autocorrelation: autocorrelation,
+		innovationScale: math.Sqrt(1 - autocorrelation*autocorrelation),
+	}
+}
+
+// починає нову реалізацію з незалежного стану
+func (e *hourlyErrors) start() {
+	e.z = e.rng.NormFloat64()
+}
+
+// похибка прогнозу для наступної години
+func (e *hourlyErrors) next() float64 {
+	e.z = e.autocorrelation*e.z + e.innovationScale*e.rng.NormFloat64()
+	return e.sample(e.z)
+}
+
+// зводить вибірку доходів у показники ризику
+func summarizeRisk(values []float64, confidence float64) RiskSummary {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	n := float64(len(sorted))
+	mean := 0.0
+	for _, value := range sorted {
+		mean += value
+	}
+	mean /= n
+	variance := 0.0
+	for _, value := range sorted {
+		variance += (value - mean) * (value - mean)
+	}
+
+	// VaR і CVaR — втрати відносно середнього на хвості (100 - confidence) %
+	tailLevel := 100 - confidence
+	threshold := percentile(sorted, tailLevel)
+	tailSum, tailCount := 0.0, 0
+	for _, value := range sorted {
+		if value > threshold {
+			break
+		}
+		tailSum += value
+		tailCount++
+	}
+
+	return RiskSummary{
+		Mean:   roundTwo(mean),
+		StdDev: roundTwo(math.Sqrt(variance / n)),
+		P10:    roundTwo(percentile(sorted, 90)),
+		P50:    roundTwo(percentile(sorted, 50)),
+		P90:    roundTwo(percentile(sorted, 10)),
+		VaR:    roundTwo(mean - threshold),
+		CVaR:   roundTwo(mean - tailSum/float64(tailCount)),
+	}
+}
+
+// моделює річний дохід погодинно з урахуванням автокореляції похибок
+func runMonteCarlo(req MonteCarloRequest) (MonteCarloResult, error) {
+	if req.Power <= 0 || req.Sigma <= 0 {
+		return MonteCarloResult{}, errors.New("потужність і σ мають бути додатними")
+	}
+	if req.Autocorrelation <= -1 || req.Autocorrelation >= 1 {
+		return MonteCarloResult{}, errors.New("коефіцієнт автокореляції має бути в межах (-1, 1)")
+	}
+	if err := req.Distribution.validate(); err != nil {
+		return MonteCarloResult{}, err
+	}
+	if req.Runs <= 0 {
+		req.Runs = 1000
+	}
+	if req.Runs > maxMonteCarloRuns {
+		req.Runs = maxMonteCarloRuns
+	}
+	if req.Confidence <= 0 || req.Confidence >= 100 {
+		req.Confidence = 95
+	}
+	if req.Bins <= 0 {
+		req.Bins = 20
+	}
+
+	rules := findMarketRules(req.Market)
+	params := req.PlantParams.withMarketDefaults(rules)
+
+	errorDistribution := req.Distribution.build(params.Power, req.Sigma)
+	hourErrors := newHourlyErrors(errorDistribution, req.Autocorrelation, req.Seed)
+	lower, upper := params.band()
+	hourRevenue := params.Power * params.Cost
+	hourPenalty := params.Power * params.PenaltyPrice
+
+	annual := make([]float64, req.Runs)
+	monthly := make([][]float64, len(monthDays))
+	for month := range monthly {
+		monthly[month] = make([]float64, req.Runs)
+	}
+
+	for run := 0; run < req.Runs; run++ {
+		hourErrors.start()
+		for month, days := range monthDays {
+			total := 0.0
+			for hour := 0; hour < days*24; hour++ {
+				actual := params.Power + hourErrors.next()
+				if actual >= lower && actual <= upper {
+					total += hourRevenue
+				} else {
+					total -= hourPenalty
+				}
+			}
+			monthly[month][run] = total
+			annual[run] += total
+		}
+	}
+
+	result := MonteCarloResult{
+		Market:     rules.Title,
+		Runs:       req.Runs,
+		Seed:       req.Seed,
+		Confidence: req.Confidence,
+		Expected:   roundTwo(calculateScenario(params, errorDistribution).Profit * 365),
+		Annual:     summarizeRisk(annual, req.Confidence),
+	}
+	for month, values := range monthly {
+		result.Monthly = append(result.Monthly, MonthRisk{
+			Month:       month + 1,
+			Name:        monthNames[month],
+			RiskSummary: summarizeRisk(values, req.Confidence),
+		})
+	}
+	histogram := newEmpiricalDistribution(annual, req.Bins)
+	for i, count := range histogram.Counts {
+		result.Histogram = append(result.Histogram, HistogramBin{
+			From:  roundTwo(histogram.Edges[i]),
+			To:    roundTwo(histogram.Edges[i+1]),
+			Count: int(count),
+			Share: roundTwo(count / float64(req.Runs) * 100),
+		})
+	}
+	return result, nil
+}
+
+// Обробник сторінки аналізу ризиків
+func handleMonteCarlo(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := template.ParseFiles("montecarlo.html")
+	if err != nil {
+		http.Error(w, "Не вдалося завантажити сторінку", http.StatusInternalServerError)
+		return
+	}
+
+	data := MonteCarloPageData{Profiles: marketProfiles}
+
+	if r.Method == http.MethodPost {
+		r.ParseForm()
+		req := MonteCarloRequest{
+			Market:       r.FormValue("market"),
+			Distribution: parseDistributionSpec(r),
+		}
+		req.Power, _ = strconv.ParseFloat(r.FormValue("power"), 64)
+		req.Cost, _ = strconv.ParseFloat(r.FormValue("cost"), 64)
+		req.TolerancePercent, _ = strconv.ParseFloat(r.FormValue("tolerancePercent"), 64)
+		req.PenaltyPrice, _ = strconv.ParseFloat(r.FormValue("penaltyPrice"), 64)
+		req.Sigma, _ = strconv.ParseFloat(r.FormValue("sigma"), 64)
+		req.Runs, _ = strconv.Atoi(r.FormValue("runs"))
+		req.Seed, _ = strconv.ParseInt(r.FormValue("seed"), 10, 64)
+		req.Autocorrelation, _ = strconv.ParseFloat(r.FormValue("autocorrelation"), 64)
+		req.Confidence, _ = strconv.ParseFloat(r.FormValue("confidence"), 64)
+		req.Bins, _ = strconv.Atoi(r.FormValue("histogramBins"))
+
+		result, err := runMonteCarlo(req)
+		if err != nil {
+			data.Error = err.Error()
+		} else {
+			data.Result = &result
+		}
+	}
+	tmpl.Execute(w, data)
+}
+
+// Обробник API аналізу ризиків
+func handleMonteCarloAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req MonteCarloRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := runMonteCarlo(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSummarizeRisk(t *testing.T) {
+	values := make([]float64, 100)
+	for i := range values {
+		values[i] = float64(100 - i)
+	}
+	// хвіст 10 %: поріг 10.9, середнє значень 1…10 дорівнює 5.5
+	want := RiskSummary{Mean: 50.5, StdDev: 28.87, P10: 90.1, P50: 50.5, P90: 10.9, VaR: 39.6, CVaR: 45}
+	if got := summarizeRisk(values, 90); got != want {
+		t.Errorf("показники ризику %+v, очікувалось %+v", got, want)
+	}
+}
+
+// гаусова копула має відтворювати квантилі розподілу похибки
+func TestErrorSamplerCopula(t *testing.T) {
+	tests := []struct {
+		name string
+		d    Distribution
+		z    float64
+		want float64
+	}{
+		{"нормальний", NormalDistribution{Mean: 0.1, Sigma: 0.5}, 1, 0.6},
+		{"Стьюдента, медіана", StudentTDistribution{Scale: 1, DOF: 5}, 0, 0},
+		{"Стьюдента, 95 %", StudentTDistribution{Scale: 1, DOF: 5}, 1.6448536269514722, 2.015048372669157},
+		{"Стьюдента, 1 %", StudentTDistribution{Scale: 1, DOF: 5}, -2.3263478740408408, -3.3649299989072},
+		{"усічений", TruncatedNormalDistribution{Sigma: 1, Lower: 0, Upper: math.Inf(1)}, 0, 0.6744897501960817},
+	}
+	for _, tt := range tests {
+		if got := newErrorSampler(tt.d)(tt.z); math.Abs(got-tt.want) > 1e-3 {
+			t.Errorf("%s: похибка %v для z=%v, очікувалось %v", tt.name, got, tt.z, tt.want)
+		}
+	}
+}
+
+func TestRunMonteCarloFixedSeed(t *testing.T) {
+	req := MonteCarloRequest{
+		PlantParams:     PlantParams{Power: 5, TolerancePercent: 5, Cost: 7, PenaltyPrice: 7},
+		Sigma:           0.25,
+		Runs:            200,
+		Seed:            42,
+		Autocorrelation: 0.5,
+	}
+	result, err := runMonteCarlo(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Annual.P10 != 116417 || result.Annual.P50 != 111650 || result.Annual.P90 != 107450 {
+		t.Errorf("P10/P50/P90 = %v/%v/%v, очікувалось 116417/111650/107450",
+			result.Annual.P10, result.Annual.P50, result.Annual.P90)
+	}
+
+	// повтор з тим самим зерном дає той самий результат
+	again, _ := runMonteCarlo(req)
+	if again.Annual != result.Annual {
+		t.Errorf("результат із зерном %d не відтворюється", req.Seed)
+	}
+
+	// середнє вибірки близьке до аналітичного очікування
+	if math.Abs(result.Annual.Mean-result.Expected) > 3*result.Annual.StdDev/math.Sqrt(float64(req.Runs)) {
+		t.Errorf("середнє %v далеке від очікуваного %v", result.Annual.Mean, result.Expected)
+	}
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// станція у складі портфеля
+type PortfolioPlant struct {
+	Name string `json:"name"`
+	PlantParams
+	Sigma float64 `json:"sigma"`
+	Bias  float64 `json:"bias"`
+}
+
+// вхідні дані для розрахунку портфеля станцій
+type PortfolioRequest struct {
+	Market      string           `json:"market"`
+	Plants      []PortfolioPlant `json:"plants"`
+	Correlation [][]float64      `json:"correlation"` // кореляція похибок; без неї похибки незалежні
+}
+
+// результат окремої станції, якби вона балансувалася самостійно
+type PortfolioPlantResult struct {
+	Name       string  `json:"name"`
+	LowerBound float64 `json:"lowerBound"`
+	UpperBound float64 `json:"upperBound"`
+	ScenarioResult
+}
+
+// результати для портфеля в цілому
+type PortfolioResult struct {
+	Market                 string                 `json:"market"`
+	Plants                 []PortfolioPlantResult `json:"plants"`
+	TotalPower             float64                `json:"totalPower"`
+	BandHalfWidth          float64                `json:"bandHalfWidth"`  // сума допустимих відхилень станцій
+	PortfolioSigma         float64                `json:"portfolioSigma"` // σ сумарної похибки з урахуванням кореляції
+	SumSigma               float64                `json:"sumSigma"`       // σ за повної кореляції
+	ImbalanceProbability   float64                `json:"imbalanceProbability"`
+	Portfolio              ScenarioResult         `json:"portfolio"`
+	SumIndividualProfit    float64                `json:"sumIndividualProfit"`
+	DiversificationBenefit float64                `json:"diversificationBenefit"`
+}
+
+// дані сторінки портфеля
+type PortfolioPageData struct {
+	Profiles []MarketRules
+	Error    string
+	Result   *PortfolioResult
+}
+
+// перевіряє кореляційну матрицю; за її відсутності повертає одиничну
+func correlationMatrix(matrix [][]float64, size int) ([][]float64, error) {
+	if len(matrix) == 0 {
+		matrix = make([][]float64, size)
+		for i := range matrix {
+			matrix[i] = make([]float64, size)
+			matrix[i][i] = 1
+		}
+		return matrix, nil
+	}
+	if len(matrix) != size {
+		return nil, fmt.Errorf("кореляційна матриця має бути розміром %d×%d", size, size)
+	}
+	for _, row := range matrix {
+		if len(row) != size {
+			return nil, fmt.Errorf("кореляційна матриця має бути розміром %d×%d", size, size)
+		}
+	}
+	for i, row := range matrix {
+		if math.Abs(row[i]-1) > 1e-9 {
+			return nil, errors.New("на діагоналі кореляційної матриці мають бути одиниці")
+		}
+		for j, value := range row {
+			if math.Abs(value) > 1 || math.Abs(value-matrix[j][i]) > 1e-9 {
+				return nil, errors.New("кореляційна матриця має бути симетричною зі значеннями в межах [-1, 1]")
+			}
+		}
+	}
+	if !isPositiveSemidefinite(matrix) {
+		return nil, errors.New("кореляційна матриця не є додатно напіввизначеною")
+	}
+	return matrix, nil
+}
+
+// перевірка розкладом Холецького з невеликим допуском на округлення
+func isPositiveSemidefinite(matrix [][]float64) bool {
+	const eps = 1e-9
+	size := len(matrix)
+	lower := make([][]float64, size)
+	for i := range lower {
+		lower[i] = make([]float64, size)
+	}
+	for i := 0; i < size; i++ {
+		for j := 0; j <= i; j++ {
+			sum := matrix[i][j]
+			for k := 0; k < j; k++ {
+				sum -= lower[i][k] * lower[j][k]
+			}
+			if i == j {
+				if sum < -eps {
+					return false
+				}
+				lower[i][i] = math.Sqrt(math.Max(sum, 0))
+			} else if lower[j][j] > eps {
+				lower[i][j] = sum / lower[j][j]
+			} else if math.Abs(sum) > eps {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// розраховує небаланс і прибуток портфеля та ефект диверсифікації
+func calculatePortfolio(req PortfolioRequest) (PortfolioResult, error) {
+	if len(req.Plants) == 0 {
+		return PortfolioResult{}, errors.New("портфель має містити хоча б одну станцію")
+	}
+	correlation, err := correlationMatrix(req.Correlation, len(req.Plants))
+	if err != nil {
+		return PortfolioResult{}, err
+	}
+
+	rules := findMarketRules(req.Market)
+	result := PortfolioResult{Market: rules.Title}
+	plants := make([]PlantParams, len(req.Plants))
+	bias, variance := 0.0, 0.0
+	revenuePerHour, penaltyPerHour := 0.0, 0.0
+
+	for i, plant := range req.Plants {
+		if plant.Power <= 0 || plant.Sigma <= 0 {
+			return PortfolioResult{}, fmt.Errorf("станція %d: потужність і σ мають бути додатними", i+1)
+		}
+		params := plant.PlantParams.withMarketDefaults(rules)
+		plants[i] = params
+		lower, upper := params.band()
+
+		name := plant.Name
+		if name == "" {
+			name = "Станція " + strconv.Itoa(i+1)
+		}
+		scenario := calculateScenario(params, NormalDistribution{Mean: plant.Bias, Sigma: plant.Sigma})
+		result.Plants = append(result.Plants, PortfolioPlantResult{
+			Name:           name,
+			LowerBound:     roundTwo(lower),
+			UpperBound:     roundTwo(upper),
+			ScenarioResult: scenario,
+		})
+
+		result.TotalPower += params.Power
+		result.BandHalfWidth += (upper - lower) / 2
+		result.SumSigma += plant.Sigma
+		result.SumIndividualProfit += scenario.Profit
+		bias += plant.Bias
+		revenuePerHour += params.Power * params.Cost
+		penaltyPerHour += params.Power * params.PenaltyPrice
+		for j, other := range req.Plants {
+			variance += correlation[i][j] * plant.Sigma * other.Sigma
+		}
+	}
+
+	// сума корельованих нормальних похибок теж має нормальний розподіл
+	portfolioErrors := NormalDistribution{Mean: bias, Sigma: math.Sqrt(math.Max(variance, 0))}
+	share := probabilityBetween(portfolioErrors, -result.BandHalfWidth, result.BandHalfWidth)
+	revenue := revenuePerHour * 24 * share
+	penalty := penaltyPerHour * 24 * (1 - share)
+
+	result.Portfolio = ScenarioResult{
+		Distribution:      portfolioErrors.Name(),
+		Deviation:         roundTwo(portfolioErrors.Sigma),
+		ShareNoImbalance:  roundTwo(share * 100),
+		EnergyNoImbalance: roundTwo(result.TotalPower * 24 * share),
+		EnergyImbalance:   roundTwo(result.TotalPower * 24 * (1 - share)),
+		Revenue:           roundTwo(revenue),
+		Penalty:           roundTwo(penalty),
+		Profit:            roundTwo(revenue - penalty),
+	}
+	result.TotalPower = roundTwo(result.TotalPower)
+	result.BandHalfWidth = roundTwo(result.BandHalfWidth)
+	result.PortfolioSigma = roundTwo(portfolioErrors.Sigma)
+	result.SumSigma = roundTwo(result.SumSigma)
+	result.ImbalanceProbability = roundTwo((1 - share) * 100)
+	result.SumIndividualProfit = roundTwo(result.SumIndividualProfit)
+	result.DiversificationBenefit = roundTwo(result.Portfolio.Profit - result.SumIndividualProfit)
+	return result, nil
+}
+
+// розбирає рядки «назва потужність σ вартість [коридор %] [ціна небалансу]»
+func parsePortfolioPlants(text string) ([]PortfolioPlant, error) {
+	var plants []PortfolioPlant
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(strings.ReplaceAll(line, ",", " "))
+		if len(fields) == 0 {
+			continue
+		}
+		plant := PortfolioPlant{}
+		if _, err := strconv.ParseFloat(fields[0], 64); err != nil {
+			plant.Name = fields[0]
+			fields = fields[1:]
+		}
+		values := parseFloatList(strings.Join(fields, " "))
+		if len(values) < 3 || len(values) != len(fields) {
+			return nil, fmt.Errorf("рядок %q: очікується потужність, σ, вартість і необов'язково коридор та ціна небалансу", strings.TrimSpace(line))
+		}
+		plant.Power, plant.Sigma, plant.Cost = values[0], values[1], values[2]
+		if len(values) > 3 {
+			plant.TolerancePercent = values[3]
+		}
+		if len(values) > 4 {
+			plant.PenaltyPrice = values[4]
+		}
+		plants = append(plants, plant)
+	}
+	return plants, nil
+}
+
+// розбирає кореляційну матрицю, по рядку матриці в кожному рядку тексту
+func parseMatrix(text string) [][]float64 {
+	var matrix [][]float64
+	for _, line := range strings.Split(text, "\n") {
+		if row := parseFloatList(line); len(row) > 0 {
+			matrix = append(matrix, row)
+		}
+	}
+	return matrix
+}
+
+// Обробник сторінки портфеля
+func handlePortfolio(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := template.ParseFiles("portfolio.html")
+	if err != nil {
+		http.Error(w, "Не вдалося завантажити сторінку", http.StatusInternalServerError)
+		return
+	}
+
+	data := PortfolioPageData{Profiles: marketProfiles}
+
+	if r.Method == http.MethodPost {
+		r.ParseForm()
+		req := PortfolioRequest{
+			Market:      r.FormValue("market"),
+			Correlation: parseMatrix(r.FormValue("correlation")),
+		}
+		req.Plants, err = parsePortfolioPlants(r.FormValue("plants"))
+		if err != nil {
+			data.Error = err.Error()
+		} else {
+			result, err := calculatePortfolio(req)
+			if err != nil {
+				data.Error = err.Error()
+			} else {
+				data.Result = &result
+			}
+		}
+	}
+	tmpl.Execute(w, data)
+}
+
+// Обробник API портфеля
+func handlePortfolioAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req PortfolioRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := calculatePortfolio(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
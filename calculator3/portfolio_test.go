@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestCorrelationMatrixValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix [][]float64
+		size   int
+		valid  bool
+	}{
+		{"за замовчуванням одинична", nil, 2, true},
+		{"корельовані похибки", [][]float64{{1, 0.5}, {0.5, 1}}, 2, true},
+		{"короткий рядок", [][]float64{{1, 0}, {}}, 2, false},
+		{"короткий останній рядок з форми", parseMatrix("1 0 0\n0 1 0\n0"), 3, false},
+		{"несиметрична", [][]float64{{1, 0.5}, {0.2, 1}}, 2, false},
+		{"не одиниці на діагоналі", [][]float64{{1, 0}, {0, 0.9}}, 2, false},
+		{"не додатно напіввизначена", [][]float64{{1, 0.9, -0.9}, {0.9, 1, 0.9}, {-0.9, 0.9, 1}}, 3, false},
+	}
+	for _, tt := range tests {
+		matrix, err := correlationMatrix(tt.matrix, tt.size)
+		if tt.valid && err != nil {
+			t.Errorf("%s: неочікувана помилка %v", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: очікувалась помилка", tt.name)
+		}
+		if tt.valid && len(matrix) != tt.size {
+			t.Errorf("%s: розмір %d, очікувалось %d", tt.name, len(matrix), tt.size)
+		}
+	}
+}
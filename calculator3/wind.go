@@ -0,0 +1,298 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// кількість інтервалів швидкості вітру при перетворенні через криву потужності
+const windSpeedSteps = 2000
+
+// точка кривої потужності вітроустановки
+type PowerCurvePoint struct {
+	Speed float64 `json:"speed"` // м/с
+	Power float64 `json:"power"` // кВт
+}
+
+// типова крива потужності установки 2 МВт; за межами точок потужність нульова
+var defaultPowerCurve = []PowerCurvePoint{
+	{3, 0}, {4, 75}, {5, 190}, {6, 350}, {7, 580}, {8, 880}, {9, 1250},
+	{10, 1600}, {11, 1850}, {12, 1980}, {13, 2000}, {25, 2000}, {25.01, 0},
+}
+
+// потужність установки за швидкості вітру з лінійною інтерполяцією
+func powerFromCurve(curve []PowerCurvePoint, speed float64) float64 {
+	if len(curve) == 0 || speed < curve[0].Speed || speed > curve[len(curve)-1].Speed {
+		return 0
+	}
+	index := sort.Search(len(curve), func(i int) bool { return curve[i].Speed >= speed })
+	if curve[index].Speed == speed || index == 0 {
+		return curve[index].Power
+	}
+	left, right := curve[index-1], curve[index]
+	return left.Power + (right.Power-left.Power)*(speed-left.Speed)/(right.Speed-left.Speed)
+}
+
+// дискретний розподіл: значення з імовірностями
+type DiscreteDistribution struct {
+	Values     []float64 // відсортовані за зростанням
+	Cumulative []float64 // накопичені ймовірності
+}
+
+// будує дискретний розподіл з довільно впорядкованих значень і ваг
+func newDiscreteDistribution(values, weights []float64) DiscreteDistribution {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return values[order[a]] < values[order[b]] })
+
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+	d := DiscreteDistribution{Values: make([]float64, len(values)), Cumulative: make([]float64, len(values))}
+	cumulative := 0.0
+	for i, index := range order {
+		cumulative += weights[index] / total
+		d.Values[i] = values[index]
+		d.Cumulative[i] = cumulative
+	}
+	return d
+}
+
+func (d DiscreteDistribution) Name() string {
+	return "Вітрова (через криву потужності)"
+}
+
+func (d DiscreteDistribution) CDF(x float64) float64 {
+	index := sort.Search(len(d.Values), func(i int) bool { return d.Values[i] > x })
+	if index == 0 {
+		return 0
+	}
+	return d.Cumulative[index-1]
+}
+
+// центральний момент заданого порядку
+func (d DiscreteDistribution) moment(order float64) (float64, float64) {
+	mean, previous := 0.0, 0.0
+	for i, value := range d.Values {
+		mean += value * (d.Cumulative[i] - previous)
+		previous = d.Cumulative[i]
+	}
+	sum := 0.0
+	previous = 0
+	for i, value := range d.Values {
+		sum += math.Pow(value-mean, order) * (d.Cumulative[i] - previous)
+		previous = d.Cumulative[i]
+	}
+	return mean, sum
+}
+
+func (d DiscreteDistribution) StdDev() float64 {
+	_, variance := d.moment(2)
+	return math.Sqrt(variance)
+}
+
+// коефіцієнт асиметрії
+func (d DiscreteDistribution) skewness() float64 {
+	stdDev := d.StdDev()
+	if stdDev == 0 {
+		return 0
+	}
+	_, third := d.moment(3)
+	return third / math.Pow(stdDev, 3)
+}
+
+// розподіл похибки генерації за нормальної похибки прогнозу швидкості вітру
+func windErrorDistribution(curve []PowerCurvePoint, turbines, speed, speedSigma float64) DiscreteDistribution {
+	forecast := powerFromCurve(curve, speed) * turbines
+	lower := math.Max(0, speed-6*speedSigma)
+	upper := speed + 6*speedSigma
+	step := (upper - lower) / windSpeedSteps
+
+	values := make([]float64, windSpeedSteps)
+	weights := make([]float64, windSpeedSteps)
+	for i := range values {
+		left := lower + float64(i)*step
+		weights[i] = normalCDF(left+step, speed, speedSigma) - normalCDF(left, speed, speedSigma)
+		values[i] = powerFromCurve(curve, left+step/2)*turbines - forecast
+	}
+	return newDiscreteDistribution(values, weights)
+}
+
+// вхідні дані калькулятора для вітрової станції
+type WindRequest struct {
+	Market             string            `json:"market"`
+	Cost               float64           `json:"cost"`
+	TolerancePercent   float64           `json:"tolerancePercent"`
+	PenaltyPrice       float64           `json:"penaltyPrice"`
+	Turbines           float64           `json:"turbines"`
+	Curve              []PowerCurvePoint `json:"curve"`
+	Speed              float64           `json:"speed"`             // прогноз швидкості вітру, м/с
+	SpeedSigmaInitial  float64           `json:"speedSigmaInitial"` // σ прогнозу швидкості, м/с
+	SpeedSigmaImproved float64           `json:"speedSigmaImproved"`
+}
+
+// результати для вітрової станції
+type WindResult struct {
+	CalculationResult
+	Speed              float64 `json:"speed"`
+	ForecastPower      float64 `json:"forecastPower"`
+	SkewnessInitial    float64 `json:"skewnessInitial"`
+	SkewnessImproved   float64 `json:"skewnessImproved"`
+	SpeedSigmaInitial  float64 `json:"speedSigmaInitial"`
+	SpeedSigmaImproved float64 `json:"speedSigmaImproved"`
+}
+
+// дані сторінки вітрової станції
+type WindPageData struct {
+	Profiles []MarketRules
+	Curve    string
+	Error    string
+	Result   *WindResult
+}
+
+// розраховує прибуток вітрової станції до та після вдосконалення прогнозу вітру
+func calculateWindProfit(req WindRequest) (WindResult, error) {
+	if len(req.Curve) == 0 {
+		req.Curve = defaultPowerCurve
+	}
+	// сортуємо копію: типова крива спільна для всіх запитів
+	req.Curve = append([]PowerCurvePoint(nil), req.Curve...)
+	sort.Slice(req.Curve, func(a, b int) bool { return req.Curve[a].Speed < req.Curve[b].Speed })
+	if req.Turbines <= 0 {
+		req.Turbines = 1
+	}
+	if req.SpeedSigmaInitial <= 0 || req.SpeedSigmaImproved <= 0 {
+		return WindResult{}, errors.New("відхилення прогнозу швидкості вітру мають бути додатними")
+	}
+
+	rules := findMarketRules(req.Market)
+	params := PlantParams{
+		Power:            powerFromCurve(req.Curve, req.Speed) * req.Turbines,
+		TolerancePercent: req.TolerancePercent,
+		Cost:             req.Cost,
+		PenaltyPrice:     req.PenaltyPrice,
+	}.withMarketDefaults(rules)
+	if params.Power <= 0 {
+		return WindResult{}, errors.New("за прогнозованої швидкості вітру генерація нульова")
+	}
+
+	initial := windErrorDistribution(req.Curve, req.Turbines, req.Speed, req.SpeedSigmaInitial)
+	improved := windErrorDistribution(req.Curve, req.Turbines, req.Speed, req.SpeedSigmaImproved)
+	initialScenario := calculateScenario(params, initial)
+	improvedScenario := calculateScenario(params, improved)
+	lower, upper := params.band()
+
+	return WindResult{
+		CalculationResult: CalculationResult{
+			Market:       rules.Title,
+			LowerBound:   roundTwo(lower),
+			UpperBound:   roundTwo(upper),
+			PenaltyPrice: params.PenaltyPrice,
+			Initial:      initialScenario,
+			Improved:     improvedScenario,
+			ProfitGain:   roundTwo(improvedScenario.Profit - initialScenario.Profit),
+		},
+		Speed:              req.Speed,
+		ForecastPower:      roundTwo(params.Power),
+		SkewnessInitial:    roundTwo(initial.skewness()),
+		SkewnessImproved:   roundTwo(improved.skewness()),
+		SpeedSigmaInitial:  req.SpeedSigmaInitial,
+		SpeedSigmaImproved: req.SpeedSigmaImproved,
+	}, nil
+}
+
+// розбирає криву потужності з рядків «швидкість потужність»
+func parsePowerCurve(text string) ([]PowerCurvePoint, error) {
+	var curve []PowerCurvePoint
+	for _, line := range strings.Split(text, "\n") {
+		values := parseFloatList(line)
+		if len(values) == 0 {
+			continue
+		}
+		if len(values) != 2 {
+			return nil, fmt.Errorf("рядок кривої потужності %q: очікується швидкість і потужність", strings.TrimSpace(line))
+		}
+		curve = append(curve, PowerCurvePoint{Speed: values[0], Power: values[1]})
+	}
+	return curve, nil
+}
+
+// форматує криву потужності для поля форми
+func formatPowerCurve(curve []PowerCurvePoint) string {
+	var lines []string
+	for _, point := range curve {
+		lines = append(lines, strconv.FormatFloat(point.Speed, 'f', -1, 64)+" "+strconv.FormatFloat(point.Power, 'f', -1, 64))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Обробник сторінки вітрової станції
+func handleWind(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := template.ParseFiles("wind.html")
+	if err != nil {
+		http.Error(w, "Не вдалося завантажити сторінку", http.StatusInternalServerError)
+		return
+	}
+
+	data := WindPageData{Profiles: marketProfiles, Curve: formatPowerCurve(defaultPowerCurve)}
+
+	if r.Method == http.MethodPost {
+		r.ParseForm()
+		req := WindRequest{Market: r.FormValue("market")}
+		req.Cost, _ = strconv.ParseFloat(r.FormValue("cost"), 64)
+		req.TolerancePercent, _ = strconv.ParseFloat(r.FormValue("tolerancePercent"), 64)
+		req.PenaltyPrice, _ = strconv.ParseFloat(r.FormValue("penaltyPrice"), 64)
+		req.Turbines, _ = strconv.ParseFloat(r.FormValue("turbines"), 64)
+		req.Speed, _ = strconv.ParseFloat(r.FormValue("speed"), 64)
+		req.SpeedSigmaInitial, _ = strconv.ParseFloat(r.FormValue("speedSigmaInitial"), 64)
+		req.SpeedSigmaImproved, _ = strconv.ParseFloat(r.FormValue("speedSigmaImproved"), 64)
+		data.Curve = r.FormValue("curve")
+
+		curve, err := parsePowerCurve(data.Curve)
+		if err != nil {
+			data.Error = err.Error()
+		} else {
+			req.Curve = curve
+			result, err := calculateWindProfit(req)
+			if err != nil {
+				data.Error = err.Error()
+			} else {
+				data.Result = &result
+			}
+		}
+	}
+	tmpl.Execute(w, data)
+}
+
+// Обробник API вітрової станції
+func handleWindAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req WindRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := calculateWindProfit(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
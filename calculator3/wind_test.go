@@ -0,0 +1,31 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// на лінійній ділянці кривої похибка потужності нормальна: ΔP = 100·Δv кВт
+func TestWindErrorDistributionShareOnLinearCurve(t *testing.T) {
+	curve := []PowerCurvePoint{{0, 0}, {20, 2000}}
+	errors := windErrorDistribution(curve, 1, 10, 1)
+	if math.Abs(errors.StdDev()-100) > 0.5 {
+		t.Errorf("σ потужності %v кВт, очікувалось 100", errors.StdDev())
+	}
+
+	params := PlantParams{Power: 1000, TolerancePercent: 5}
+	want := 2*normalCDF(0.5, 0, 1) - 1 // коридор ±50 кВт = ±0,5σ
+	// похибка дискретизації — не більше одного кроку швидкості (0,6 кВт) на кожній межі
+	if got := shareWithinBand(params, errors); math.Abs(got-want) > 3e-3 {
+		t.Errorf("частка без небалансу %v, очікувалось %v", got, want)
+	}
+}
+
+// вище 13 м/с турбіна видає номінальну потужність, тож похибка швидкості майже не дає небалансу
+func TestWindErrorDistributionShareAtRatedPower(t *testing.T) {
+	errors := windErrorDistribution(defaultPowerCurve, 10, 15, 0.5)
+	params := PlantParams{Power: 20000, TolerancePercent: 5}
+	if got := shareWithinBand(params, errors); got < 0.9999 {
+		t.Errorf("частка без небалансу %v, очікувалось близько 1", got)
+	}
+}
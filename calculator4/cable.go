@@ -0,0 +1,234 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+)
+
+// вхідні дані для вибору перерізу кабелю
+type CableParams struct {
+	Voltage             float64 `json:"voltage"`             // номінальна напруга, кВ
+	ShortCircuitCurrent float64 `json:"shortCircuitCurrent"` // струм КЗ, А
+	DisconnectionTime   float64 `json:"disconnectionTime"`   // час вимкнення КЗ, с
+	LoadPower           float64 `json:"loadPower"`           // розрахункове навантаження, кВА
+	UtilizationHours    float64 `json:"utilizationHours"`    // число годин використання максимуму, год/рік
+	Material            string  `json:"material"`            // al або cu
+	Insulation          string  `json:"insulation"`          // paper, pvc або xlpe
+	Length              float64 `json:"length"`              // довжина лінії, км; 0 — без перевірки втрати напруги
+	PowerFactor         float64 `json:"powerFactor"`         // cos φ навантаження
+	MaxVoltageDrop      float64 `json:"maxVoltageDrop"`      // допустима втрата напруги, %
+	MaxLossPercent      float64 `json:"maxLossPercent"`      // допустимі втрати активної потужності, %
+}
+
+// результати розрахунку перерізу кабелю
+type CableResult struct {
+	NormalCurrent    float64        `json:"normalCurrent"`
+	EmergencyCurrent float64        `json:"emergencyCurrent"`
+	EconomicDensity  float64        `json:"economicDensity"`
+	ThermalConstant  float64        `json:"thermalConstant"`
+	EconomicSection  float64        `json:"economicSection"`
+	ThermalSection   float64        `json:"thermalSection"`
+	Selection        CableSelection `json:"selection"`
+	Check            *LineCheck     `json:"check,omitempty"` // перевірка вибраного кабелю, якщо задано довжину
+}
+
+// значення за замовчуванням, з якими калькулятор працював раніше
+var defaultCableParams = CableParams{
+	ShortCircuitCurrent: 2500,
+	DisconnectionTime:   2.5,
+	LoadPower:           1300,
+	UtilizationHours:    4000,
+	Material:            "al",
+	Insulation:          "paper",
+	PowerFactor:         0.9,
+	MaxVoltageDrop:      5,
+	MaxLossPercent:      defaultMaxLossPercent,
+}
+
+// економічна густина струму, А/мм², для Tм 1000–3000, 3000–5000 і понад 5000 год
+var economicDensityTable = map[string]map[string][3]float64{
+	"paper": {"cu": {3.0, 2.5, 2.0}, "al": {1.6, 1.4, 1.2}},
+	"pvc":   {"cu": {3.5, 3.1, 2.7}, "al": {1.9, 1.7, 1.6}},
+	"xlpe":  {"cu": {3.5, 3.1, 2.7}, "al": {1.9, 1.7, 1.6}},
+}
+
+// термічний коефіцієнт Ст, А·с½/мм², за матеріалом жил та ізоляцією
+var thermalConstantTable = map[string]map[string]float64{
+	"paper": {"cu": 141, "al": 92},
+	"pvc":   {"cu": 115, "al": 76},
+	"xlpe":  {"cu": 143, "al": 94},
+}
+
+// знаходить економічну густину струму за матеріалом, ізоляцією та Тм
+func lookupEconomicDensity(material, insulation string, hours float64) (float64, error) {
+	densities, ok := economicDensityTable[insulation][material]
+	if !ok {
+		return 0, fmt.Errorf("невідоме поєднання матеріалу %q та ізоляції %q", material, insulation)
+	}
+	switch {
+	case hours <= 3000:
+		return densities[0], nil
+	case hours <= 5000:
+		return densities[1], nil
+	}
+	return densities[2], nil
+}
+
+// знаходить термічний коефіцієнт за матеріалом та ізоляцією
+func lookupThermalConstant(material, insulation string) (float64, error) {
+	constant, ok := thermalConstantTable[insulation][material]
+	if !ok {
+		return 0, fmt.Errorf("невідоме поєднання матеріалу %q та ізоляції %q", material, insulation)
+	}
+	return constant, nil
+}
+
+// розраховує економічний і термічний перерізи для двох паралельних кабелів і вибирає кабель з каталогу
+func calculateCableParameters(params CableParams) (CableResult, error) {
+	if params.Voltage <= 0 || params.LoadPower <= 0 {
+		return CableResult{}, fmt.Errorf("напруга і навантаження мають бути додатними")
+	}
+	if params.DisconnectionTime < 0 || params.ShortCircuitCurrent < 0 {
+		return CableResult{}, fmt.Errorf("струм КЗ і час відключення не можуть бути від'ємними")
+	}
+	if params.PowerFactor <= 0 || params.PowerFactor > 1 {
+		params.PowerFactor = defaultCableParams.PowerFactor
+	}
+	if params.MaxVoltageDrop <= 0 {
+		params.MaxVoltageDrop = defaultCableParams.MaxVoltageDrop
+	}
+	economicDensity, err := lookupEconomicDensity(params.Material, params.Insulation, params.UtilizationHours)
+	if err != nil {
+		return CableResult{}, err
+	}
+	thermalConstant, err := lookupThermalConstant(params.Material, params.Insulation)
+	if err != nil {
+		return CableResult{}, err
+	}
+
+	inNormal := params.LoadPower / (2 * math.Sqrt(3) * params.Voltage)
+
+	result := CableResult{
+		NormalCurrent:    inNormal,
+		EmergencyCurrent: 2 * inNormal,
+		EconomicDensity:  economicDensity,
+		ThermalConstant:  thermalConstant,
+		EconomicSection:  inNormal / economicDensity,
+		ThermalSection:   params.ShortCircuitCurrent * math.Sqrt(params.DisconnectionTime) / thermalConstant,
+	}
+	result.Selection, err = selectCable(params, result)
+	if err != nil {
+		return CableResult{}, err
+	}
+	if params.Length > 0 {
+		check, err := checkSelectedCable(params, &result)
+		if err != nil {
+			return CableResult{}, err
+		}
+		result.Check = &check
+	}
+	return result, nil
+}
+
+// перевіряє лінію з вибраним кабелем; якщо перевірка не пройдена, збільшує переріз
+// до найменшого стандартного, з яким лінія проходить перевірку
+func checkSelectedCable(params CableParams, result *CableResult) (LineCheck, error) {
+	selection := &result.Selection
+	sinPhi := math.Sqrt(1 - params.PowerFactor*params.PowerFactor)
+	lineParams := LineCheckParams{
+		Voltage:          params.Voltage,
+		Length:           params.Length,
+		ActivePower:      params.LoadPower * params.PowerFactor,
+		ReactivePower:    params.LoadPower * sinPhi,
+		Material:         params.Material,
+		Insulation:       params.Insulation,
+		Cables:           2,
+		UtilizationHours: params.UtilizationHours,
+		MaxVoltageDrop:   params.MaxVoltageDrop,
+		MaxLossPercent:   params.MaxLossPercent,
+	}
+
+	var check LineCheck
+	for _, section := range standardSections {
+		if section < selection.Cable.Section {
+			continue
+		}
+		lineParams.Section = section
+		var err error
+		check, err = checkLine(lineParams)
+		if err != nil {
+			return LineCheck{}, err
+		}
+		if check.Passed {
+			break
+		}
+	}
+	if !check.Passed {
+		return LineCheck{}, fmt.Errorf("жоден стандартний переріз до %.0f мм² не проходить перевірку лінії: %s",
+			standardSections[len(standardSections)-1], strings.Join(check.Violations, "; "))
+	}
+	if check.Cable.Section == selection.Cable.Section {
+		return check, nil
+	}
+
+	// переріз збільшено: визначальною стає перевірка лінії
+	maxLoss := params.MaxLossPercent
+	if maxLoss <= 0 {
+		maxLoss = defaultMaxLossPercent
+	}
+	selection.Criteria = append(selection.Criteria, CriterionCheck{
+		Name:       "перевірка лінії за струмом, втратою напруги та втратами потужності",
+		Required:   fmt.Sprintf("ΔU ≤ %.1f %%, ΔP ≤ %.1f %%", params.MaxVoltageDrop, maxLoss),
+		MinSection: check.Cable.Section,
+	})
+	selection.Governing = nil
+	for i := range selection.Criteria {
+		selection.Criteria[i].Governing = selection.Criteria[i].MinSection == check.Cable.Section
+		if selection.Criteria[i].Governing {
+			selection.Governing = append(selection.Governing, selection.Criteria[i].Name)
+		}
+	}
+	selection.Cable = check.Cable
+	selection.VoltageDrop = voltageDropPercent(check.Cable, result.NormalCurrent, params.Length, params.PowerFactor, params.Voltage)
+	return check, nil
+}
+
+// текстовий звіт для сторінки калькулятора
+func (r CableResult) report() string {
+	text := fmt.Sprintf(
+		"Струм нормального режиму: %.2f А\nСтрум післяаварійного режиму: %.2f А\n"+
+			"Економічна густина струму: %.2f А/мм²\nКоефіцієнт Ст: %.0f\n"+
+			"Економічний переріз: %.2f мм²\nТермічний переріз: %.2f мм²\n\n%s",
+		r.NormalCurrent, r.EmergencyCurrent, r.EconomicDensity,
+		r.ThermalConstant, r.EconomicSection, r.ThermalSection, r.Selection.report())
+	if r.Check != nil {
+		text += "\n\n" + r.Check.report()
+	}
+	return text
+}
+
+// Обробник API розрахунку перерізу кабелю
+func cableAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := defaultCableParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := calculateCableParameters(params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// значення початкової версії калькулятора: алюмінієві жили, паперова ізоляція, Ст = 92
+func TestCalculateCableParametersMatchesBaseline(t *testing.T) {
+	tests := []struct {
+		voltage  float64
+		economic float64
+		thermal  float64
+		section  float64
+	}{
+		{voltage: 10, economic: 26.81, thermal: 42.97, section: 50},
+		{voltage: 6, economic: 44.68, thermal: 42.97, section: 50},
+	}
+	for _, tt := range tests {
+		params := defaultCableParams
+		params.Voltage = tt.voltage
+		result, err := calculateCableParameters(params)
+		if err != nil {
+			t.Fatalf("U=%v кВ: %v", tt.voltage, err)
+		}
+		if result.EconomicDensity != 1.4 || result.ThermalConstant != 92 {
+			t.Errorf("U=%v кВ: jек = %v, Ст = %v, очікувалось 1.4 і 92", tt.voltage, result.EconomicDensity, result.ThermalConstant)
+		}
+		if math.Abs(result.EconomicSection-tt.economic) > 0.005 || math.Abs(result.ThermalSection-tt.thermal) > 0.005 {
+			t.Errorf("U=%v кВ: перерізи %.2f і %.2f мм², очікувалось %.2f і %.2f мм²",
+				tt.voltage, result.EconomicSection, result.ThermalSection, tt.economic, tt.thermal)
+		}
+		if result.Selection.Cable.Section != tt.section {
+			t.Errorf("U=%v кВ: вибрано %v мм², очікувалось %v мм²", tt.voltage, result.Selection.Cable.Section, tt.section)
+		}
+	}
+}
+
+func TestCalculateCableParametersRaisesSectionForLineCheck(t *testing.T) {
+	params := defaultCableParams
+	params.Voltage = 10
+	params.Length = 8
+	params.MaxLossPercent = 1
+	result, err := calculateCableParameters(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Check == nil || !result.Check.Passed {
+		t.Fatalf("перевірка лінії не пройдена: %+v", result.Check)
+	}
+	if result.Selection.Cable.Section != 185 || result.Check.Cable.Section != 185 {
+		t.Errorf("вибрано %v мм², очікувалось 185 мм²", result.Selection.Cable.Section)
+	}
+
+	params.Length = 40
+	if _, err := calculateCableParameters(params); err == nil {
+		t.Error("для лінії 40 км очікувалась помилка: жоден переріз не проходить перевірку")
+	}
+}
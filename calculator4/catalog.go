@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+)
+
+// стандартний ряд перерізів жил, мм²
+var standardSections = []float64{16, 25, 35, 50, 70, 95, 120, 150, 185, 240}
+
+// питомий індуктивний опір трижильного кабелю 6–10 кВ для кожного стандартного перерізу, Ом/км
+var sectionReactance = []float64{0.113, 0.099, 0.095, 0.090, 0.086, 0.083, 0.081, 0.079, 0.077, 0.075}
+
+// питомий опір матеріалу жил за робочої температури, Ом·мм²/км
+var materialResistivity = map[string]float64{"al": 31.5, "cu": 18.9}
+
+// орієнтовні тривало допустимі струми кабелів 10 кВ при прокладанні в землі, А
+var permissibleCurrentTable = map[string]map[string][]float64{
+	"paper": {
+		"al": {75, 90, 115, 140, 165, 205, 240, 275, 310, 355},
+		"cu": {95, 120, 150, 180, 215, 265, 310, 355, 400, 460},
+	},
+	"pvc": {
+		"al": {70, 85, 105, 125, 155, 185, 210, 240, 270, 315},
+		"cu": {90, 110, 135, 165, 200, 240, 270, 305, 345, 400},
+	},
+	"xlpe": {
+		"al": {100, 125, 150, 180, 220, 265, 300, 340, 380, 440},
+		"cu": {125, 160, 195, 235, 285, 340, 385, 435, 490, 570},
+	},
+}
+
+var materialNames = map[string]string{"al": "алюмінієві", "cu": "мідні"}
+
+var insulationNames = map[string]string{
+	"paper": "паперова ізоляція",
+	"pvc":   "полівінілхлоридна ізоляція",
+	"xlpe":  "ізоляція зі зшитого поліетилену",
+}
+
+// кабель з каталогу
+type CableType struct {
+	Material           string  `json:"material"`
+	Insulation         string  `json:"insulation"`
+	Section            float64 `json:"section"`            // мм²
+	PermissibleCurrent float64 `json:"permissibleCurrent"` // А
+	Resistance         float64 `json:"resistance"`         // Ом/км
+	Reactance          float64 `json:"reactance"`          // Ом/км
+}
+
+// назва кабелю для звітів
+func (c CableType) String() string {
+	return fmt.Sprintf("%.0f мм², жили %s, %s", c.Section, materialNames[c.Material], insulationNames[c.Insulation])
+}
+
+// кабелі заданого матеріалу та ізоляції у порядку зростання перерізу
+func catalogCables(material, insulation string) ([]CableType, error) {
+	currents, ok := permissibleCurrentTable[insulation][material]
+	if !ok {
+		return nil, fmt.Errorf("у каталозі немає кабелів з матеріалом %q та ізоляцією %q", material, insulation)
+	}
+	cables := make([]CableType, len(standardSections))
+	for i, section := range standardSections {
+		cables[i] = CableType{
+			Material:           material,
+			Insulation:         insulation,
+			Section:            section,
+			PermissibleCurrent: currents[i],
+			Resistance:         materialResistivity[material] / section,
+			Reactance:          sectionReactance[i],
+		}
+	}
+	return cables, nil
+}
+
+// втрата напруги в лінії у відсотках від номінальної
+func voltageDropPercent(cable CableType, current, length, powerFactor, voltage float64) float64 {
+	sinPhi := math.Sqrt(1 - powerFactor*powerFactor)
+	drop := math.Sqrt(3) * current * (cable.Resistance*powerFactor + cable.Reactance*sinPhi) * length
+	return drop / (voltage * 1000) * 100
+}
+
+// результат перевірки за одним критерієм: найменший переріз, що його задовольняє
+type CriterionCheck struct {
+	Name       string  `json:"name"`
+	Required   string  `json:"required"`
+	MinSection float64 `json:"minSection"`
+	Governing  bool    `json:"governing"`
+}
+
+// вибраний кабель і пояснення вибору
+type CableSelection struct {
+	Cable       CableType        `json:"cable"`
+	Criteria    []CriterionCheck `json:"criteria"`
+	Governing   []string         `json:"governing"`
+	VoltageDrop float64          `json:"voltageDrop"` // %
+}
+
+// вибирає найменший стандартний переріз, що задовольняє всі критерії
+func selectCable(params CableParams, result CableResult) (CableSelection, error) {
+	cables, err := catalogCables(params.Material, params.Insulation)
+	if err != nil {
+		return CableSelection{}, err
+	}
+
+	type criterion struct {
+		name     string
+		required string
+		ok       func(CableType) bool
+	}
+	criteria := []criterion{
+		{
+			name:     "економічна густина струму",
+			required: fmt.Sprintf("S ≥ %.2f мм²", result.EconomicSection),
+			ok:       func(c CableType) bool { return c.Section >= result.EconomicSection },
+		},
+		{
+			name:     "термічна стійкість до струму КЗ",
+			required: fmt.Sprintf("S ≥ %.2f мм²", result.ThermalSection),
+			ok:       func(c CableType) bool { return c.Section >= result.ThermalSection },
+		},
+		{
+			name:     "тривало допустимий струм у післяаварійному режимі",
+			required: fmt.Sprintf("Iдоп ≥ %.2f А", result.EmergencyCurrent),
+			ok:       func(c CableType) bool { return c.PermissibleCurrent >= result.EmergencyCurrent },
+		},
+	}
+	if params.Length > 0 {
+		criteria = append(criteria, criterion{
+			name:     "допустима втрата напруги",
+			required: fmt.Sprintf("ΔU ≤ %.1f %%", params.MaxVoltageDrop),
+			ok: func(c CableType) bool {
+				return voltageDropPercent(c, result.NormalCurrent, params.Length, params.PowerFactor, params.Voltage) <= params.MaxVoltageDrop
+			},
+		})
+	}
+
+	selection := CableSelection{}
+	chosen := -1
+	for _, criterion := range criteria {
+		index := -1
+		for i, cable := range cables {
+			if criterion.ok(cable) {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return CableSelection{}, fmt.Errorf("жоден стандартний переріз до %.0f мм² не задовольняє критерій «%s» (%s)",
+				standardSections[len(standardSections)-1], criterion.name, criterion.required)
+		}
+		selection.Criteria = append(selection.Criteria, CriterionCheck{
+			Name:       criterion.name,
+			Required:   criterion.required,
+			MinSection: cables[index].Section,
+		})
+		if index > chosen {
+			chosen = index
+		}
+	}
+
+	selection.Cable = cables[chosen]
+	for i := range selection.Criteria {
+		if selection.Criteria[i].MinSection == selection.Cable.Section {
+			selection.Criteria[i].Governing = true
+			selection.Governing = append(selection.Governing, selection.Criteria[i].Name)
+		}
+	}
+	selection.VoltageDrop = voltageDropPercent(selection.Cable, result.NormalCurrent, params.Length, params.PowerFactor, params.Voltage)
+	return selection, nil
+}
+
+// текстовий звіт про вибір кабелю
+func (s CableSelection) report() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Вибраний кабель: %s\n", s.Cable)
+	fmt.Fprintf(&b, "Тривало допустимий струм: %.0f А, r0 = %.3f Ом/км, x0 = %.3f Ом/км\n",
+		s.Cable.PermissibleCurrent, s.Cable.Resistance, s.Cable.Reactance)
+	if s.VoltageDrop > 0 {
+		fmt.Fprintf(&b, "Втрата напруги в нормальному режимі: %.2f %%\n", s.VoltageDrop)
+	}
+	b.WriteString("Перевірка за критеріями:\n")
+	for _, c := range s.Criteria {
+		mark := ""
+		if c.Governing {
+			mark = " — визначальний"
+		}
+		fmt.Fprintf(&b, "  %s (%s): не менше %.0f мм²%s\n", c.Name, c.Required, c.MinSection, mark)
+	}
+	fmt.Fprintf(&b, "Визначальний критерій: %s", strings.Join(s.Governing, ", "))
+	return b.String()
+}
+
+// Обробник API каталогу кабелів
+func cableCatalogHandler(w http.ResponseWriter, r *http.Request) {
+	var catalog []CableType
+	for _, insulation := range []string{"paper", "pvc", "xlpe"} {
+		for _, material := range []string{"al", "cu"} {
+			cables, _ := catalogCables(material, insulation)
+			catalog = append(catalog, cables...)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(catalog)
+}
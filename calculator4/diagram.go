@@ -0,0 +1,412 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"strings"
+)
+
+// розміри сітки однолінійної схеми, пікселі
+const (
+	diagramColumn = 340 // відстань між шинами одного рівня
+	diagramRow    = 190 // відстань між рівнями
+	diagramMargin = 60
+	diagramBar    = 160 // довжина зображення шин
+	diagramOffset = 75  // зсув паралельних з'єднань між тими самими шинами
+)
+
+// запит побудови однолінійної схеми
+type DiagramRequest struct {
+	Network    Network       `json:"network"`
+	Annotation string        `json:"annotation"` // shortcircuit, loadflow або порожньо — лише схема
+	Buses      []LoadFlowBus `json:"buses"`      // режимні дані для loadflow
+}
+
+// дані сторінки однолінійної схеми
+type DiagramPageData struct {
+	Request    string
+	Annotation string
+	Result     string
+	Chart      template.HTML
+}
+
+// приклад для сторінки: мережа з розрахунку КЗ разом з режимними даними вузлів
+const exampleDiagram = `{
+  "network": {
+    "basePower": 100,
+    "buses": [
+      {"id": "B1", "name": "Шини 115 кВ", "voltage": 115},
+      {"id": "B2", "name": "Шини 115 кВ ПС-2", "voltage": 115},
+      {"id": "B3", "name": "Шини 10,5 кВ", "voltage": 10.5}
+    ],
+    "lines": [
+      {"id": "L1", "from": "B1", "to": "B2", "length": 40, "resistance": 0.12, "reactance": 0.4}
+    ],
+    "transformers": [
+      {"id": "T1", "from": "B2", "to": "B3", "power": 25, "uk": 10.5, "losses": 120}
+    ],
+    "generators": [
+      {"id": "G1", "bus": "B3", "power": 12.5, "reactance": 0.14, "xr": 40}
+    ],
+    "systems": [
+      {"id": "C1", "bus": "B1", "shortCircuitPower": 3000, "xr": 15}
+    ],
+    "motors": [
+      {"id": "M1", "bus": "B3", "power": 3200, "startingRatio": 5.5, "powerFactor": 0.87}
+    ]
+  },
+  "buses": [
+    {"id": "B1", "type": "slack", "voltage": 1.05},
+    {"id": "B2", "type": "pq", "loadActive": 20, "loadReactive": 10},
+    {"id": "B3", "type": "pv", "voltage": 1.02, "generationActive": 10, "loadActive": 25, "loadReactive": 12}
+  ]
+}`
+
+// розміщення вузла на схемі
+type diagramPoint struct {
+	x, y float64
+}
+
+// розставляє шини за рівнями: від вузлів з джерелами вниз по зв'язках мережі
+func diagramLayout(n Network, roots []string) (map[string]diagramPoint, int, int) {
+	neighbours := map[string][]string{}
+	link := func(a, b string) {
+		neighbours[a] = append(neighbours[a], b)
+		neighbours[b] = append(neighbours[b], a)
+	}
+	for _, line := range n.Lines {
+		link(line.From, line.To)
+	}
+	for _, t := range n.Transformers {
+		link(t.From, t.To)
+	}
+	for _, t := range n.Transformers3 {
+		link(t.High, t.Medium)
+		link(t.High, t.Low)
+	}
+
+	level := map[string]int{}
+	var order [][]string
+	visit := func(start string) {
+		queue := []string{start}
+		if _, ok := level[start]; ok {
+			return
+		}
+		level[start] = 0
+		if len(order) == 0 {
+			order = append(order, nil)
+		}
+		order[0] = append(order[0], start)
+		for len(queue) > 0 {
+			bus := queue[0]
+			queue = queue[1:]
+			for _, next := range neighbours[bus] {
+				if _, ok := level[next]; ok {
+					continue
+				}
+				level[next] = level[bus] + 1
+				if len(order) <= level[next] {
+					order = append(order, nil)
+				}
+				order[level[next]] = append(order[level[next]], next)
+				queue = append(queue, next)
+			}
+		}
+	}
+	for _, root := range roots {
+		visit(root)
+	}
+	for _, bus := range n.Buses {
+		visit(bus.ID)
+	}
+
+	positions := map[string]diagramPoint{}
+	columns := 0
+	for row, buses := range order {
+		for column, bus := range buses {
+			positions[bus] = diagramPoint{
+				x: diagramMargin + diagramBar/2 + float64(column)*diagramColumn,
+				y: diagramMargin + 70 + float64(row)*diagramRow,
+			}
+		}
+		if len(buses) > columns {
+			columns = len(buses)
+		}
+	}
+	return positions, columns, len(order)
+}
+
+// підпис потужності P + jQ, МВт і Мвар
+func powerLabel(active, reactive float64) string {
+	// додавання нуля прибирає від'ємний нуль після округлення
+	active = math.Round(active*10)/10 + 0
+	reactive = math.Round(reactive*10)/10 + 0
+	if reactive < 0 {
+		return fmt.Sprintf("%.1f − j%.1f", active, -reactive)
+	}
+	return fmt.Sprintf("%.1f + j%.1f", active, reactive)
+}
+
+// будує SVG однолінійної схеми з підписами результатів розрахунку
+func renderDiagram(req DiagramRequest) (string, error) {
+	n := req.Network
+	if len(n.Buses) == 0 {
+		return "", errors.New("мережа не містить жодного вузла")
+	}
+	// перевірка зв'язків і параметрів тією самою схемою заміщення, що й у розрахунках
+	if _, err := n.model(positiveSequence); err != nil {
+		return "", err
+	}
+
+	busNotes := map[string][]string{}
+	flows := map[string]BranchFlow{}
+	loads := map[string]string{}
+	var roots []string
+	switch req.Annotation {
+	case "shortcircuit":
+		// матриці опорів обертаються один раз, струми КЗ кожного вузла беруться з діагоналей
+		models, matrices, err := n.sequenceImpedances()
+		if err != nil {
+			return "", err
+		}
+		m := models[positiveSequence]
+		z := matrices[positiveSequence]
+		emf := complex(1, 0)
+		for i, bus := range m.buses {
+			summary := m.faultSummary(i, matrices, 1)
+			_, _, peak, _ := m.peakCurrent(n, z, i, faultVoltages(z, i, emf), emf, summary.ThreePhase, 0.1)
+			busNotes[bus.ID] = []string{
+				fmt.Sprintf("I''(3) = %.2f кА", summary.ThreePhase),
+				fmt.Sprintf("iу = %.2f кА", peak),
+				fmt.Sprintf("I(2) = %.2f кА", summary.TwoPhase),
+				fmt.Sprintf("I(1) = %.2f кА", summary.SinglePhase),
+			}
+		}
+	case "loadflow":
+		flow, err := calculateLoadFlow(LoadFlowRequest{Network: n, Buses: req.Buses})
+		if err != nil {
+			return "", err
+		}
+		if !flow.Diagnostics.Converged {
+			return "", errors.New(flow.Diagnostics.Message)
+		}
+		for _, bus := range flow.Buses {
+			busNotes[bus.ID] = []string{
+				fmt.Sprintf("U = %.2f кВ (%.3f в. о.)", bus.Voltage, bus.Magnitude),
+				fmt.Sprintf("δ = %.2f°", bus.Angle),
+			}
+			if bus.LoadActive != 0 || bus.LoadReactive != 0 {
+				loads[bus.ID] = powerLabel(bus.LoadActive, bus.LoadReactive)
+			}
+			if bus.Type == "slack" {
+				roots = append(roots, bus.ID)
+			}
+		}
+		for _, b := range flow.Branches {
+			flows[b.ID] = b
+		}
+	case "":
+	default:
+		return "", fmt.Errorf("невідомий тип підписів %q: має бути shortcircuit або loadflow", req.Annotation)
+	}
+	for _, s := range n.Systems {
+		roots = append(roots, s.Bus)
+	}
+	for _, g := range n.Generators {
+		roots = append(roots, g.Bus)
+	}
+
+	positions, columns, rows := diagramLayout(n, roots)
+	width := 2*diagramMargin + diagramBar + (columns-1)*diagramColumn + 80
+	height := 2*diagramMargin + 110 + (rows-1)*diagramRow
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Arial" font-size="11">`, width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="white"/>`, width, height)
+	text := func(x, y float64, anchor, color, value string) {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="%s" fill="%s">%s</text>`, x, y, anchor, color, template.HTMLEscapeString(value))
+	}
+
+	// з'єднання між шинами: ламана лінія з позначкою елемента посередині
+	parallel := map[string]int{}
+	connect := func(from, to diagramPoint, pair string) (diagramPoint, float64) {
+		offset := float64(parallel[pair]) * diagramOffset
+		parallel[pair]++
+		x1, x2 := from.x+offset, to.x+offset
+		middle := (from.y + to.y) / 2
+		if from.y == to.y {
+			middle = from.y + 50 + offset
+			x1, x2 = from.x+40, to.x-40
+		}
+		fmt.Fprintf(&b, `<polyline fill="none" stroke="black" points="%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f"/>`,
+			x1, from.y, x1, middle, x2, middle, x2, to.y)
+		return diagramPoint{(x1 + x2) / 2, middle}, offset
+	}
+	pairKey := func(a, c string) string {
+		if a > c {
+			a, c = c, a
+		}
+		return a + "|" + c
+	}
+	for _, line := range n.Lines {
+		from, to := positions[line.From], positions[line.To]
+		if from.y > to.y {
+			from, to = to, from
+		}
+		mid, _ := connect(from, to, pairKey(line.From, line.To))
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="8" height="16" fill="white" stroke="black"/>`, mid.x-4, mid.y-8)
+		text(mid.x+10, mid.y-2, "start", "black", fmt.Sprintf("%s %g км", line.ID, line.Length))
+		if flow, ok := flows[line.ID]; ok {
+			text(mid.x+10, mid.y+12, "start", "#1f77b4", powerLabel(flow.ActiveFrom, flow.ReactiveFrom))
+		}
+	}
+	for _, t := range n.Transformers {
+		from, to := positions[t.From], positions[t.To]
+		if from.y > to.y {
+			from, to = to, from
+		}
+		mid, _ := connect(from, to, pairKey(t.From, t.To))
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="10" fill="white" stroke="black"/><circle cx="%.1f" cy="%.1f" r="10" fill="none" stroke="black"/>`,
+			mid.x, mid.y-6, mid.x, mid.y+6)
+		text(mid.x+16, mid.y-2, "start", "black", fmt.Sprintf("%s %g МВА", t.ID, t.Power))
+		if flow, ok := flows[t.ID]; ok {
+			text(mid.x+16, mid.y+12, "start", "#1f77b4", powerLabel(flow.ActiveFrom, flow.ReactiveFrom))
+		}
+	}
+	for _, t := range n.Transformers3 {
+		high := positions[t.High]
+		offset := float64(max(parallel[pairKey(t.High, t.Medium)], parallel[pairKey(t.High, t.Low)])) * diagramOffset
+		parallel[pairKey(t.High, t.Medium)]++
+		parallel[pairKey(t.High, t.Low)]++
+		star := diagramPoint{high.x + offset, high.y + diagramRow/2}
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="black"/>`, star.x, high.y, star.x, star.y-12)
+		for _, winding := range []struct{ bus, id string }{{t.Medium, t.ID + "-СН"}, {t.Low, t.ID + "-НН"}} {
+			end := positions[winding.bus]
+			end.x += offset
+			fmt.Fprintf(&b, `<polyline fill="none" stroke="black" points="%.1f,%.1f %.1f,%.1f %.1f,%.1f"/>`,
+				star.x, star.y+6, end.x, star.y+6, end.x, end.y)
+			// промінь обмотки спрямований від шин до нульової точки, тому потік до шин — на його кінці
+			if flow, ok := flows[winding.id]; ok {
+				text(end.x+6, star.y+20, "start", "#1f77b4", powerLabel(flow.ActiveTo, flow.ReactiveTo))
+			}
+		}
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="10" fill="white" stroke="black"/><circle cx="%.1f" cy="%.1f" r="10" fill="none" stroke="black"/><circle cx="%.1f" cy="%.1f" r="10" fill="none" stroke="black"/>`,
+			star.x, star.y-6, star.x-6, star.y+5, star.x+6, star.y+5)
+		text(star.x+20, star.y-8, "start", "black", fmt.Sprintf("%s %g МВА", t.ID, t.Power))
+		if flow, ok := flows[t.ID+"-ВН"]; ok {
+			text(star.x+20, star.y-22, "start", "#1f77b4", powerLabel(flow.ActiveFrom, flow.ReactiveFrom))
+		}
+	}
+
+	// джерела, двигуни та навантаження розміщуються над шинами між точками приєднання віток
+	// (зсуви 0 і diagramOffset); порожній символ означає навантаження і позначається стрілкою
+	attached := map[string]int{}
+	attach := func(bus, symbol, label string) {
+		p, ok := positions[bus]
+		if !ok {
+			return
+		}
+		slot := attached[bus]
+		attached[bus]++
+		slots := []float64{-66, -30, 38}
+		x := p.x + slots[slot%len(slots)] - float64(slot/len(slots))*110
+		labelY := p.y - 50 - float64(slot%2)*12
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="black"/>`, x, p.y, x, p.y-26)
+		if symbol == "" {
+			fmt.Fprintf(&b, `<polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="black"/>`, x-5, p.y-26, x+5, p.y-26, x, p.y-36)
+		} else {
+			fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="10" fill="white" stroke="black"/>`, x, p.y-36)
+			text(x, p.y-32, "middle", "black", symbol)
+		}
+		text(x, labelY, "middle", "black", label)
+	}
+	for _, s := range n.Systems {
+		attach(s.Bus, "С", fmt.Sprintf("%s %g МВА", s.ID, s.ShortCircuitPower))
+	}
+	for _, g := range n.Generators {
+		attach(g.Bus, "~", fmt.Sprintf("%s %g МВА", g.ID, g.Power))
+	}
+	for _, m := range n.Motors {
+		attach(m.Bus, "М", fmt.Sprintf("%s %g кВт", m.ID, m.Power))
+	}
+	for _, bus := range n.Buses {
+		if load, ok := loads[bus.ID]; ok {
+			attach(bus.ID, "", load)
+		}
+	}
+
+	for _, bus := range n.Buses {
+		p := positions[bus.ID]
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="black" stroke-width="5"/>`,
+			p.x-diagramBar/2, p.y, p.x+diagramBar/2, p.y)
+		name := bus.ID
+		if bus.Name != "" {
+			name += " " + bus.Name
+		}
+		text(p.x+diagramBar/2+6, p.y-4, "start", "black", name)
+		text(p.x+diagramBar/2+6, p.y+10, "start", "black", fmt.Sprintf("%g кВ", bus.Voltage))
+		for i, note := range busNotes[bus.ID] {
+			text(p.x+diagramBar/2+6, p.y+24+14*float64(i), "start", "#d62728", note)
+		}
+	}
+	b.WriteString(`</svg>`)
+	return b.String(), nil
+}
+
+// Обробник сторінки однолінійної схеми
+func task9Handler(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := template.ParseFiles("task9.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := DiagramPageData{Request: exampleDiagram, Annotation: "shortcircuit"}
+
+	if r.Method == http.MethodPost {
+		data.Request = r.FormValue("request")
+		data.Annotation = r.FormValue("annotation")
+		var req DiagramRequest
+		err := json.Unmarshal([]byte(data.Request), &req)
+		if err != nil {
+			err = fmt.Errorf("некоректний опис мережі: %v", err)
+		} else {
+			req.Annotation = data.Annotation
+			var svg string
+			svg, err = renderDiagram(req)
+			if err == nil {
+				data.Chart = template.HTML(svg)
+			}
+		}
+		if err != nil {
+			data.Result = "Помилка: " + err.Error()
+		}
+	}
+	tmpl.Execute(w, data)
+}
+
+// Обробник API однолінійної схеми; повертає SVG для вбудовування у звіти
+func diagramAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req DiagramRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	svg, err := renderDiagram(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Write([]byte(svg))
+}
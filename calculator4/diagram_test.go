@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"testing"
+)
+
+func parseExampleDiagram(t *testing.T) DiagramRequest {
+	t.Helper()
+	var req DiagramRequest
+	if err := json.Unmarshal([]byte(exampleDiagram), &req); err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+// схема має містити всі шини та з'єднання і бути коректним XML за будь-яких підписів
+func TestDiagramContainsNetwork(t *testing.T) {
+	for _, annotation := range []string{"", "shortcircuit", "loadflow"} {
+		req := parseExampleDiagram(t)
+		req.Annotation = annotation
+		svg, err := renderDiagram(req)
+		if err != nil {
+			t.Fatalf("%q: %v", annotation, err)
+		}
+
+		n := req.Network
+		var ids []string
+		for _, bus := range n.Buses {
+			ids = append(ids, bus.ID)
+		}
+		for _, line := range n.Lines {
+			ids = append(ids, line.ID)
+		}
+		for _, tr := range n.Transformers {
+			ids = append(ids, tr.ID)
+		}
+		for _, id := range ids {
+			if !strings.Contains(svg, id) {
+				t.Errorf("%q: схема не містить %s", annotation, id)
+			}
+		}
+
+		decoder := xml.NewDecoder(strings.NewReader(svg))
+		for {
+			_, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%q: некоректний XML: %v", annotation, err)
+			}
+		}
+	}
+}
+
+// підписи КЗ на схемі мають збігатися з розрахунком КЗ у кожному вузлі
+func TestDiagramShortCircuitNotes(t *testing.T) {
+	req := parseExampleDiagram(t)
+	req.Annotation = "shortcircuit"
+	svg, err := renderDiagram(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bus := range req.Network.Buses {
+		fault, err := calculateNetworkFault(NetworkFaultRequest{Network: req.Network, Bus: bus.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, note := range []string{
+			fmt.Sprintf("I''(3) = %.2f кА", fault.ThreePhase),
+			fmt.Sprintf("iу = %.2f кА", fault.PeakCurrent),
+		} {
+			if !strings.Contains(svg, template.HTMLEscapeString(note)) {
+				t.Errorf("%s: схема не містить підпису %q", bus.ID, note)
+			}
+		}
+	}
+}
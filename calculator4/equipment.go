@@ -0,0 +1,331 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// апарат або шини з каталогу
+type EquipmentType struct {
+	ID              string  `json:"id"`
+	Kind            string  `json:"kind"` // breaker, disconnector, ct або busbar
+	Name            string  `json:"name"`
+	Voltage         float64 `json:"voltage,omitempty"`         // номінальна напруга, кВ
+	MaxVoltage      float64 `json:"maxVoltage,omitempty"`      // найбільша робоча напруга, кВ
+	RatedCurrent    float64 `json:"ratedCurrent"`              // номінальний або тривало допустимий струм, А
+	BreakingCurrent float64 `json:"breakingCurrent,omitempty"` // номінальний струм вимкнення, кА
+	AperiodicRated  float64 `json:"aperiodicRated,omitempty"`  // нормований вміст аперіодичної складової βнорм, %
+	PeakWithstand   float64 `json:"peakWithstand,omitempty"`   // струм електродинамічної стійкості, кА
+	ThermalCurrent  float64 `json:"thermalCurrent,omitempty"`  // струм термічної стійкості, кА
+	ThermalTime     float64 `json:"thermalTime,omitempty"`     // час термічної стійкості, с
+	Width           float64 `json:"width,omitempty"`           // ширина смуги шин, мм
+	Thickness       float64 `json:"thickness,omitempty"`       // товщина смуги шин, мм
+	ThermalConstant float64 `json:"thermalConstant,omitempty"` // коефіцієнт С шин, А·с½/мм²
+	AllowableStress float64 `json:"allowableStress,omitempty"` // допустима механічна напруга в матеріалі шин, МПа
+}
+
+// каталог комутаційних апаратів, трансформаторів струму та шин
+var equipmentCatalog = []EquipmentType{
+	{ID: "vv-10-20-1000", Kind: "breaker", Name: "ВВ/TEL-10-20/1000", Voltage: 10, MaxVoltage: 12, RatedCurrent: 1000, BreakingCurrent: 20, AperiodicRated: 40, PeakWithstand: 51, ThermalCurrent: 20, ThermalTime: 3},
+	{ID: "vv-10-31.5-2000", Kind: "breaker", Name: "ВВ/TEL-10-31,5/2000", Voltage: 10, MaxVoltage: 12, RatedCurrent: 2000, BreakingCurrent: 31.5, AperiodicRated: 40, PeakWithstand: 80, ThermalCurrent: 31.5, ThermalTime: 3},
+	{ID: "vgt-110-40-2500", Kind: "breaker", Name: "ВГТ-110-40/2500", Voltage: 110, MaxVoltage: 126, RatedCurrent: 2500, BreakingCurrent: 40, AperiodicRated: 45, PeakWithstand: 102, ThermalCurrent: 40, ThermalTime: 3},
+	{ID: "rvz-10-1000", Kind: "disconnector", Name: "РВЗ-10/1000", Voltage: 10, MaxVoltage: 12, RatedCurrent: 1000, PeakWithstand: 81, ThermalCurrent: 31.5, ThermalTime: 4},
+	{ID: "rvrz-10-2500", Kind: "disconnector", Name: "РВРЗ-10/2500", Voltage: 10, MaxVoltage: 12, RatedCurrent: 2500, PeakWithstand: 125, ThermalCurrent: 45, ThermalTime: 4},
+	{ID: "rndz-110-1000", Kind: "disconnector", Name: "РНДЗ-110/1000", Voltage: 110, MaxVoltage: 126, RatedCurrent: 1000, PeakWithstand: 80, ThermalCurrent: 31.5, ThermalTime: 3},
+	{ID: "tol-10-600", Kind: "ct", Name: "ТОЛ-10 600/5", Voltage: 10, MaxVoltage: 12, RatedCurrent: 600, PeakWithstand: 52, ThermalCurrent: 20, ThermalTime: 3},
+	{ID: "tplk-10-1000", Kind: "ct", Name: "ТПЛК-10 1000/5", Voltage: 10, MaxVoltage: 12, RatedCurrent: 1000, PeakWithstand: 74.5, ThermalCurrent: 28.3, ThermalTime: 3},
+	{ID: "tfzm-110-600", Kind: "ct", Name: "ТФЗМ-110 600/5", Voltage: 110, MaxVoltage: 126, RatedCurrent: 600, PeakWithstand: 84, ThermalCurrent: 33, ThermalTime: 3},
+	{ID: "ad31t-60x6", Kind: "busbar", Name: "Шини АД31Т 60×6", RatedCurrent: 870, Width: 60, Thickness: 6, ThermalConstant: 91, AllowableStress: 70},
+	{ID: "ad31t-80x8", Kind: "busbar", Name: "Шини АД31Т 80×8", RatedCurrent: 1320, Width: 80, Thickness: 8, ThermalConstant: 91, AllowableStress: 70},
+	{ID: "ad31t-100x10", Kind: "busbar", Name: "Шини АД31Т 100×10", RatedCurrent: 1820, Width: 100, Thickness: 10, ThermalConstant: 91, AllowableStress: 70},
+	{ID: "mt-80x8", Kind: "busbar", Name: "Шини МТ 80×8", RatedCurrent: 1690, Width: 80, Thickness: 8, ThermalConstant: 171, AllowableStress: 140},
+}
+
+var equipmentKindNames = map[string]string{
+	"breaker":      "вимикач",
+	"disconnector": "роз'єднувач",
+	"ct":           "трансформатор струму",
+	"busbar":       "шини",
+}
+
+// знаходить апарат у каталозі
+func findEquipment(id string) (EquipmentType, bool) {
+	for _, device := range equipmentCatalog {
+		if device.ID == id {
+			return device, true
+		}
+	}
+	return EquipmentType{}, false
+}
+
+// запит перевірки обладнання; струми КЗ задаються вручну або розраховуються для вузла мережі
+type VerificationRequest struct {
+	Devices           []string             `json:"devices"`
+	Voltage           float64              `json:"voltage"`           // напруга мережі, кВ
+	OperatingCurrent  float64              `json:"operatingCurrent"`  // максимальний робочий струм, А
+	InitialCurrent    float64              `json:"initialCurrent"`    // I'', кА
+	PeakCurrent       float64              `json:"peakCurrent"`       // iу, кА
+	BreakingCurrent   float64              `json:"breakingCurrent"`   // Iτ, кА; 0 — дорівнює I''
+	ThermalImpulse    float64              `json:"thermalImpulse"`    // Bk, кА²·с; 0 — розраховується
+	DisconnectionTime float64              `json:"disconnectionTime"` // час вимкнення КЗ, с
+	TimeConstant      float64              `json:"timeConstant"`      // стала часу аперіодичної складової, с
+	ContactTime       float64              `json:"contactTime"`       // τ — час від початку КЗ до розходження контактів вимикача, с
+	Span              float64              `json:"span"`              // проліт між ізоляторами шин, м
+	PhaseSpacing      float64              `json:"phaseSpacing"`      // відстань між фазами шин, м
+	Fault             *NetworkFaultRequest `json:"fault,omitempty"`   // розрахунок КЗ у мережі замість ручного введення
+}
+
+// перевірка за одним критерієм: номінальне значення має бути не меншим за розрахункове
+type EquipmentCheck struct {
+	Criterion  string  `json:"criterion"`
+	Unit       string  `json:"unit"`
+	Rated      float64 `json:"rated"`
+	Calculated float64 `json:"calculated"`
+	Passed     bool    `json:"passed"`
+}
+
+// результати перевірки одного апарата
+type DeviceVerification struct {
+	Device EquipmentType    `json:"device"`
+	Checks []EquipmentCheck `json:"checks"`
+	Passed bool             `json:"passed"`
+}
+
+// результати перевірки обладнання
+type VerificationResult struct {
+	Voltage          float64              `json:"voltage"`
+	OperatingCurrent float64              `json:"operatingCurrent"`
+	InitialCurrent   float64              `json:"initialCurrent"`
+	PeakCurrent      float64              `json:"peakCurrent"`
+	BreakingCurrent  float64              `json:"breakingCurrent"`
+	AperiodicCurrent float64              `json:"aperiodicCurrent"` // iа,τ, кА
+	ThermalImpulse   float64              `json:"thermalImpulse"`
+	Devices          []DeviceVerification `json:"devices"`
+	Passed           bool                 `json:"passed"`
+}
+
+// дані сторінки перевірки обладнання
+type EquipmentPageData struct {
+	Catalog []EquipmentType
+	Result  string
+}
+
+// значення за замовчуванням для необов'язкових полів
+var defaultVerificationRequest = VerificationRequest{
+	DisconnectionTime: 1,
+	TimeConstant:      0.05,
+	ContactTime:       0.05,
+	Span:              1,
+	PhaseSpacing:      0.25,
+}
+
+// додає перевірку за умовою «номінальне ≥ розрахункового»
+func (d *DeviceVerification) check(criterion, unit string, rated, calculated float64) {
+	d.Checks = append(d.Checks, EquipmentCheck{
+		Criterion:  criterion,
+		Unit:       unit,
+		Rated:      rated,
+		Calculated: calculated,
+		Passed:     rated >= calculated,
+	})
+}
+
+// механічна напруга в шинах, покладених плазом, від ударного струму, МПа
+func busbarStress(device EquipmentType, peak, span, spacing float64) float64 {
+	force := math.Sqrt(3) * 1e-7 * math.Pow(peak*1000, 2) / spacing // Н/м
+	moment := force * span * span / 10                              // Н·м
+	modulus := device.Width * device.Thickness * device.Thickness / 6 * 1e-9
+	return moment / modulus / 1e6
+}
+
+// перевіряє вибрані апарати на робочий струм, вимикальну здатність, динамічну і термічну стійкість
+func verifyEquipment(req VerificationRequest) (VerificationResult, error) {
+	if req.Fault != nil {
+		fault, err := calculateNetworkFault(*req.Fault)
+		if err != nil {
+			return VerificationResult{}, err
+		}
+		if req.Voltage <= 0 {
+			req.Voltage = fault.Voltage
+		}
+		timeConstant, _ := peakFactor(complex(fault.Thevenin.R, fault.Thevenin.X))
+		req.InitialCurrent = fault.ThreePhase
+		req.PeakCurrent = fault.PeakCurrent
+		req.BreakingCurrent = fault.BreakingCurrent
+		req.TimeConstant = timeConstant
+		req.ThermalImpulse = 0
+		if req.Fault.MinimumTime > 0 {
+			req.ContactTime = req.Fault.MinimumTime
+		}
+	}
+	if len(req.Devices) == 0 {
+		return VerificationResult{}, errors.New("виберіть хоча б один апарат")
+	}
+	if req.InitialCurrent <= 0 || req.PeakCurrent <= 0 {
+		return VerificationResult{}, errors.New("початковий та ударний струми КЗ мають бути додатними")
+	}
+	if req.BreakingCurrent <= 0 {
+		req.BreakingCurrent = req.InitialCurrent
+	}
+	if req.ThermalImpulse <= 0 {
+		req.ThermalImpulse = req.InitialCurrent * req.InitialCurrent * (req.DisconnectionTime + req.TimeConstant)
+	}
+	if req.Span <= 0 || req.PhaseSpacing <= 0 {
+		req.Span, req.PhaseSpacing = defaultVerificationRequest.Span, defaultVerificationRequest.PhaseSpacing
+	}
+	if req.ContactTime <= 0 {
+		req.ContactTime = defaultVerificationRequest.ContactTime
+	}
+	// аперіодична складова в момент розходження контактів: iа,τ = √2·Iп,τ·e^(−τ/Ta)
+	aperiodic := 0.0
+	if req.TimeConstant > 0 {
+		aperiodic = math.Sqrt2 * req.BreakingCurrent * math.Exp(-req.ContactTime/req.TimeConstant)
+	}
+
+	result := VerificationResult{
+		Voltage:          req.Voltage,
+		OperatingCurrent: req.OperatingCurrent,
+		InitialCurrent:   req.InitialCurrent,
+		PeakCurrent:      req.PeakCurrent,
+		BreakingCurrent:  req.BreakingCurrent,
+		AperiodicCurrent: aperiodic,
+		ThermalImpulse:   req.ThermalImpulse,
+		Passed:           true,
+	}
+	for _, id := range req.Devices {
+		device, ok := findEquipment(id)
+		if !ok {
+			return VerificationResult{}, fmt.Errorf("апарата %q немає в каталозі", id)
+		}
+		v := DeviceVerification{Device: device}
+		if device.Kind != "busbar" {
+			v.check("найбільша робоча напруга", "кВ", device.MaxVoltage, req.Voltage)
+		}
+		v.check("тривалий струм", "А", device.RatedCurrent, req.OperatingCurrent)
+		if device.Kind == "breaker" {
+			v.check("струм вимкнення", "кА", device.BreakingCurrent, req.BreakingCurrent)
+			if device.AperiodicRated > 0 {
+				v.check("аперіодична складова струму вимкнення √2·Iвимк·βнорм", "кА",
+					math.Sqrt2*device.BreakingCurrent*device.AperiodicRated/100, aperiodic)
+			}
+		}
+		if device.Kind == "busbar" {
+			v.check("мінімальний переріз за термічною стійкістю", "мм²",
+				device.Width*device.Thickness, math.Sqrt(req.ThermalImpulse*1e6)/device.ThermalConstant)
+			v.check("механічна напруга від ударного струму", "МПа",
+				device.AllowableStress, busbarStress(device, req.PeakCurrent, req.Span, req.PhaseSpacing))
+		} else {
+			v.check("електродинамічна стійкість", "кА", device.PeakWithstand, req.PeakCurrent)
+			v.check("термічна стійкість Iт²·tт ≥ Bk", "кА²·с", device.ThermalCurrent*device.ThermalCurrent*device.ThermalTime, req.ThermalImpulse)
+		}
+		v.Passed = true
+		for _, c := range v.Checks {
+			v.Passed = v.Passed && c.Passed
+		}
+		result.Passed = result.Passed && v.Passed
+		result.Devices = append(result.Devices, v)
+	}
+	return result, nil
+}
+
+// текстовий звіт перевірки
+func (r VerificationResult) report() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Розрахункові умови: U = %g кВ, Iроб = %.0f А, I'' = %.3f кА, iу = %.3f кА, Iτ = %.3f кА, iа,τ = %.3f кА, Bk = %.2f кА²·с\n",
+		r.Voltage, r.OperatingCurrent, r.InitialCurrent, r.PeakCurrent, r.BreakingCurrent, r.AperiodicCurrent, r.ThermalImpulse)
+	for _, device := range r.Devices {
+		status := "відповідає"
+		if !device.Passed {
+			status = "НЕ ВІДПОВІДАЄ"
+		}
+		fmt.Fprintf(&b, "\n%s (%s): %s\n", device.Device.Name, equipmentKindNames[device.Device.Kind], status)
+		for _, c := range device.Checks {
+			mark := "так"
+			if !c.Passed {
+				mark = "НІ"
+			}
+			fmt.Fprintf(&b, "  %s: каталог %.2f %s, розрахунок %.2f %s — %s\n", c.Criterion, c.Rated, c.Unit, c.Calculated, c.Unit, mark)
+		}
+	}
+	if r.Passed {
+		b.WriteString("\nУсе обладнання проходить перевірку")
+	} else {
+		b.WriteString("\nЧастина обладнання не проходить перевірку")
+	}
+	return b.String()
+}
+
+// Обробник сторінки перевірки обладнання
+func task6Handler(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := template.ParseFiles("task6.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := EquipmentPageData{Catalog: equipmentCatalog}
+
+	if r.Method == http.MethodPost {
+		r.ParseForm()
+		req := defaultVerificationRequest
+		req.Devices = r.Form["devices"]
+		err := parseFloatFields(r, []floatField{
+			{"voltage", &req.Voltage},
+			{"operatingCurrent", &req.OperatingCurrent},
+			{"initialCurrent", &req.InitialCurrent},
+			{"peakCurrent", &req.PeakCurrent},
+			{"disconnectionTime", &req.DisconnectionTime},
+			{"timeConstant", &req.TimeConstant},
+			{"contactTime", &req.ContactTime},
+		})
+		req.BreakingCurrent, _ = strconv.ParseFloat(r.FormValue("breakingCurrent"), 64)
+		req.Span, _ = strconv.ParseFloat(r.FormValue("span"), 64)
+		req.PhaseSpacing, _ = strconv.ParseFloat(r.FormValue("phaseSpacing"), 64)
+		if err == nil {
+			var result VerificationResult
+			result, err = verifyEquipment(req)
+			if err == nil {
+				data.Result = result.report()
+			}
+		}
+		if err != nil {
+			data.Result = "Помилка: " + err.Error()
+		}
+	}
+	tmpl.Execute(w, data)
+}
+
+// Обробник API перевірки обладнання
+func equipmentVerifyAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req := defaultVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := verifyEquipment(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// Обробник API каталогу обладнання
+func equipmentCatalogHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(equipmentCatalog)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// результат перевірки апарата за назвою критерію
+func findCheck(t *testing.T, v DeviceVerification, criterion string) EquipmentCheck {
+	t.Helper()
+	for _, c := range v.Checks {
+		if c.Criterion == criterion {
+			return c
+		}
+	}
+	t.Fatalf("%s: немає перевірки %q", v.Device.Name, criterion)
+	return EquipmentCheck{}
+}
+
+func TestVerifyEquipmentThermalAndDynamicLimits(t *testing.T) {
+	const (
+		dynamic = "електродинамічна стійкість"
+		thermal = "термічна стійкість Iт²·tт ≥ Bk"
+	)
+	tests := []struct {
+		name           string
+		peak           float64 // кА
+		thermalImpulse float64 // кА²·с; 0 — I''²·(tвідкл + Ta)
+		dynamicPassed  bool
+		thermalPassed  bool
+	}{
+		{name: "в межах стійкості", peak: 38, dynamicPassed: true, thermalPassed: true},
+		{name: "ударний струм понад 51 кА", peak: 60, thermalPassed: true},
+		{name: "тепловий імпульс понад 20²·3 кА²·с", peak: 38, thermalImpulse: 1500, dynamicPassed: true},
+	}
+	for _, tt := range tests {
+		req := defaultVerificationRequest
+		req.Devices = []string{"vv-10-20-1000"}
+		req.Voltage, req.OperatingCurrent, req.InitialCurrent = 10, 800, 15
+		req.PeakCurrent, req.ThermalImpulse = tt.peak, tt.thermalImpulse
+		result, err := verifyEquipment(req)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		breaker := result.Devices[0]
+		if c := findCheck(t, breaker, dynamic); c.Passed != tt.dynamicPassed || c.Rated != 51 {
+			t.Errorf("%s: динамічна стійкість %+v, очікувалось пройдено = %v", tt.name, c, tt.dynamicPassed)
+		}
+		if c := findCheck(t, breaker, thermal); c.Passed != tt.thermalPassed || c.Rated != 1200 {
+			t.Errorf("%s: термічна стійкість %+v, очікувалось пройдено = %v", tt.name, c, tt.thermalPassed)
+		}
+		if want := tt.dynamicPassed && tt.thermalPassed; breaker.Passed != want || result.Passed != want {
+			t.Errorf("%s: загальний результат %v, очікувалось %v", tt.name, breaker.Passed, want)
+		}
+	}
+	// Bk = 15²·(1 + 0,05) = 236,25 кА²·с
+	req := defaultVerificationRequest
+	req.Devices = []string{"vv-10-20-1000"}
+	req.Voltage, req.OperatingCurrent, req.InitialCurrent, req.PeakCurrent = 10, 800, 15, 38
+	result, _ := verifyEquipment(req)
+	if math.Abs(result.ThermalImpulse-236.25) > 1e-9 {
+		t.Errorf("Bk = %v, очікувалось 236.25", result.ThermalImpulse)
+	}
+}
+
+// шини 60×6 мм (360 мм², С = 91): Smin = √Bk / C
+func TestVerifyEquipmentBusbarThermalLimit(t *testing.T) {
+	const criterion = "мінімальний переріз за термічною стійкістю"
+	for _, tt := range []struct {
+		impulse float64
+		passed  bool
+	}{
+		{impulse: 236.25, passed: true},
+		{impulse: 1500, passed: false},
+	} {
+		req := defaultVerificationRequest
+		req.Devices = []string{"ad31t-60x6"}
+		req.OperatingCurrent, req.InitialCurrent, req.PeakCurrent, req.ThermalImpulse = 800, 15, 38, tt.impulse
+		result, err := verifyEquipment(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := findCheck(t, result.Devices[0], criterion)
+		if want := math.Sqrt(tt.impulse*1e6) / 91; c.Passed != tt.passed || math.Abs(c.Calculated-want) > 1e-9 {
+			t.Errorf("Bk = %v: %+v, очікувалось Smin = %.1f мм², пройдено = %v", tt.impulse, c, want, tt.passed)
+		}
+	}
+}
+
+// iа,τ = √2·Iп,τ·e^(−τ/Ta) порівнюється з √2·Iвимк·βнорм/100 = √2·20·0,4 = 11,31 кА
+func TestVerifyEquipmentAperiodicBreakingCurrent(t *testing.T) {
+	const criterion = "аперіодична складова струму вимкнення √2·Iвимк·βнорм"
+	tests := []struct {
+		timeConstant float64
+		aperiodic    float64 // кА
+		passed       bool
+	}{
+		{timeConstant: 0.05, aperiodic: math.Sqrt2 * 15 * math.Exp(-1), passed: true},      // 7,80 кА
+		{timeConstant: 0.15, aperiodic: math.Sqrt2 * 15 * math.Exp(-1.0/3), passed: false}, // 15,20 кА
+	}
+	for _, tt := range tests {
+		req := defaultVerificationRequest
+		req.Devices = []string{"vv-10-20-1000"}
+		req.Voltage, req.OperatingCurrent, req.InitialCurrent, req.PeakCurrent = 10, 800, 15, 38
+		req.TimeConstant, req.ContactTime = tt.timeConstant, 0.05
+		result, err := verifyEquipment(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := findCheck(t, result.Devices[0], criterion)
+		if math.Abs(c.Calculated-tt.aperiodic) > 1e-9 || math.Abs(c.Rated-math.Sqrt2*20*0.4) > 1e-9 || c.Passed != tt.passed {
+			t.Errorf("Ta = %v с: %+v, очікувалось iа,τ = %.2f кА, пройдено = %v", tt.timeConstant, c, tt.aperiodic, tt.passed)
+		}
+		if findCheck(t, result.Devices[0], "струм вимкнення").Passed != true {
+			t.Errorf("Ta = %v с: симетричний струм вимкнення 15 кА має проходити", tt.timeConstant)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+module calculator4
+
+go 1.21
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// κ = 1,02 + 0,98·e^(−3R/X), IEC 60909-0, формула (55)
+func TestKappaFromRatio(t *testing.T) {
+	tests := []struct {
+		ratio float64 // R/X
+		want  float64
+	}{
+		{ratio: 0, want: 2},
+		{ratio: 0.07, want: 1.8143},
+		{ratio: 0.1, want: 1.7460},
+		{ratio: 0.3, want: 1.4185},
+		{ratio: 1, want: 1.0688},
+	}
+	for _, tt := range tests {
+		if got := kappaFromRatio(complex(tt.ratio, 1)); math.Abs(got-tt.want) > 1e-4 {
+			t.Errorf("R/X = %v: κ = %.4f, очікувалось %.4f", tt.ratio, got, tt.want)
+		}
+	}
+}
+
+// метод B: 1,15·κ, але не більше 2 (1,8 до 1 кВ)
+func TestIECPeakFactor(t *testing.T) {
+	tests := []struct {
+		ratio   float64 // R/X
+		voltage float64 // кВ
+		want    float64
+	}{
+		{ratio: 0, voltage: 10, want: 2},
+		{ratio: 0.1, voltage: 10, want: 2},
+		{ratio: 0.3, voltage: 10, want: 1.15 * 1.4185},
+		{ratio: 1, voltage: 10, want: 1.15 * 1.0688},
+		{ratio: 0.1, voltage: 0.4, want: 1.8},
+	}
+	for _, tt := range tests {
+		if got := iecPeakFactor(complex(tt.ratio, 1), tt.voltage); math.Abs(got-tt.want) > 1e-4 {
+			t.Errorf("R/X = %v, U = %v кВ: 1,15·κ = %.4f, очікувалось %.4f", tt.ratio, tt.voltage, got, tt.want)
+		}
+	}
+}
+
+// μ за IEC 60909-0, формула (70), для різних мінімальних часів розмикання
+func TestDecayFactor(t *testing.T) {
+	tests := []struct {
+		ratio, tmin float64 // I''kG/IrG, с
+		want        float64
+	}{
+		{ratio: 2, tmin: 0.02, want: 1},
+		{ratio: 3, tmin: 0.02, want: 0.9592},
+		{ratio: 5, tmin: 0.05, want: 0.8238},
+		{ratio: 5, tmin: 0.1, want: 0.7654},
+		{ratio: 8, tmin: 0.25, want: 0.6050},
+	}
+	for _, tt := range tests {
+		if got := decayFactor(tt.ratio, tt.tmin); math.Abs(got-tt.want) > 1e-4 {
+			t.Errorf("I''kG/IrG = %v, tmin = %v с: μ = %.4f, очікувалось %.4f", tt.ratio, tt.tmin, got, tt.want)
+		}
+	}
+}
+
+// q за IEC 60909-0, формула (73): m — потужність на пару полюсів, МВт
+func TestInductionMotorBreakingFactor(t *testing.T) {
+	tests := []struct {
+		power     float64 // кВт
+		polePairs int
+		ratio     float64
+		tmin      float64
+		want      float64 // μ·q
+	}{
+		{power: 1000, polePairs: 2, ratio: 5, tmin: 0.1, want: 0.7654 * 0.4868},
+		{power: 1000, polePairs: 1, ratio: 2, tmin: 0.25, want: 0.26},
+		{power: 20000, polePairs: 1, ratio: 2, tmin: 0.02, want: 1}, // q обмежується одиницею
+		{power: 10, polePairs: 4, ratio: 2, tmin: 0.25, want: 0},    // q не буває від'ємним
+		{power: 1000, polePairs: 2, ratio: 6, tmin: 0.1, want: 0.7256 * 0.4868},
+	}
+	for _, tt := range tests {
+		g := MotorGroup{Kind: "induction", Power: tt.power, PolePairs: tt.polePairs}
+		if got := g.breakingFactor(tt.ratio, tt.tmin, true); math.Abs(got-tt.want) > 1e-3 {
+			t.Errorf("P = %v кВт, p = %d, tmin = %v с: μ·q = %.4f, очікувалось %.4f",
+				tt.power, tt.polePairs, tt.tmin, got, tt.want)
+		}
+	}
+}
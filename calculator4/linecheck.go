@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// вхідні дані перевірки лінії на втрату напруги та втрати потужності
+type LineCheckParams struct {
+	Voltage          float64 `json:"voltage"`          // номінальна напруга, кВ
+	Length           float64 `json:"length"`           // довжина лінії, км
+	ActivePower      float64 `json:"activePower"`      // активне навантаження лінії, кВт
+	ReactivePower    float64 `json:"reactivePower"`    // реактивне навантаження лінії, квар
+	Material         string  `json:"material"`         // al або cu
+	Insulation       string  `json:"insulation"`       // paper, pvc або xlpe
+	Section          float64 `json:"section"`          // переріз жил, мм²
+	Cables           int     `json:"cables"`           // кількість паралельних кабелів
+	UtilizationHours float64 `json:"utilizationHours"` // число годин використання максимуму, год/рік
+	MaxVoltageDrop   float64 `json:"maxVoltageDrop"`   // допустима втрата напруги, %
+	MaxLossPercent   float64 `json:"maxLossPercent"`   // допустимі втрати активної потужності, %
+}
+
+// результати перевірки лінії
+type LineCheck struct {
+	Cable          CableType `json:"cable"`
+	Cables         int       `json:"cables"`
+	Current        float64   `json:"current"`        // струм одного кабелю, А
+	VoltageDrop    float64   `json:"voltageDrop"`    // %
+	PowerLoss      float64   `json:"powerLoss"`      // кВт
+	LossPercent    float64   `json:"lossPercent"`    // % від активного навантаження
+	LossHours      float64   `json:"lossHours"`      // час найбільших втрат τ, год/рік
+	EnergyLoss     float64   `json:"energyLoss"`     // кВт·год/рік
+	EnergyLossPart float64   `json:"energyLossPart"` // % від переданої за рік енергії
+	Passed         bool      `json:"passed"`
+	Violations     []string  `json:"violations"`
+}
+
+// дані сторінки перевірки лінії
+type LineCheckPageData struct {
+	Sections []float64
+	Result   string
+}
+
+// допустимі втрати потужності за замовчуванням, %
+const defaultMaxLossPercent = 5.0
+
+// знаходить кабель у каталозі за матеріалом, ізоляцією та перерізом
+func findCable(material, insulation string, section float64) (CableType, error) {
+	cables, err := catalogCables(material, insulation)
+	if err != nil {
+		return CableType{}, err
+	}
+	for _, cable := range cables {
+		if cable.Section == section {
+			return cable, nil
+		}
+	}
+	return CableType{}, fmt.Errorf("переріз %g мм² відсутній у стандартному ряді", section)
+}
+
+// час найбільших втрат за емпіричною формулою τ = (0,124 + Tм/10⁴)²·8760
+func lossHours(utilizationHours float64) float64 {
+	return math.Pow(0.124+utilizationHours/10000, 2) * 8760
+}
+
+// розраховує втрату напруги, втрати потужності та енергії і порівнює їх з допустимими
+func checkLine(params LineCheckParams) (LineCheck, error) {
+	if params.Voltage <= 0 || params.Length <= 0 {
+		return LineCheck{}, errors.New("напруга і довжина лінії мають бути додатними")
+	}
+	if params.ActivePower < 0 || params.UtilizationHours < 0 || params.UtilizationHours > 8760 {
+		return LineCheck{}, errors.New("активне навантаження має бути невід'ємним, а Тм — у межах 0–8760 год")
+	}
+	if params.Cables <= 0 {
+		params.Cables = 1
+	}
+	if params.MaxVoltageDrop <= 0 {
+		params.MaxVoltageDrop = defaultCableParams.MaxVoltageDrop
+	}
+	if params.MaxLossPercent <= 0 {
+		params.MaxLossPercent = defaultMaxLossPercent
+	}
+	cable, err := findCable(params.Material, params.Insulation, params.Section)
+	if err != nil {
+		return LineCheck{}, err
+	}
+
+	// навантаження розподіляється між паралельними кабелями порівну
+	n := float64(params.Cables)
+	p, q := params.ActivePower/n, params.ReactivePower/n
+	r, x := cable.Resistance*params.Length, cable.Reactance*params.Length
+	u := params.Voltage
+
+	check := LineCheck{
+		Cable:       cable,
+		Cables:      params.Cables,
+		Current:     math.Hypot(p, q) / (math.Sqrt(3) * u),
+		VoltageDrop: (p*r + q*x) / (u * u) / 10,
+		PowerLoss:   n * (p*p + q*q) / (u * u) * r / 1000,
+		LossHours:   lossHours(params.UtilizationHours),
+	}
+	check.EnergyLoss = check.PowerLoss * check.LossHours
+	if params.ActivePower > 0 {
+		check.LossPercent = check.PowerLoss / params.ActivePower * 100
+	}
+	if energy := params.ActivePower * params.UtilizationHours; energy > 0 {
+		check.EnergyLossPart = check.EnergyLoss / energy * 100
+	}
+
+	if check.Current > cable.PermissibleCurrent {
+		check.Violations = append(check.Violations,
+			fmt.Sprintf("струм %.2f А перевищує тривало допустимий %.0f А", check.Current, cable.PermissibleCurrent))
+	}
+	if check.VoltageDrop > params.MaxVoltageDrop {
+		check.Violations = append(check.Violations,
+			fmt.Sprintf("втрата напруги %.2f %% перевищує допустиму %.1f %%", check.VoltageDrop, params.MaxVoltageDrop))
+	}
+	if check.LossPercent > params.MaxLossPercent {
+		check.Violations = append(check.Violations,
+			fmt.Sprintf("втрати потужності %.2f %% перевищують допустимі %.1f %%", check.LossPercent, params.MaxLossPercent))
+	}
+	check.Passed = len(check.Violations) == 0
+	return check, nil
+}
+
+// текстовий звіт про перевірку лінії
+func (c LineCheck) report() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Перевірка лінії: %d × %s\n", c.Cables, c.Cable)
+	fmt.Fprintf(&b, "Струм одного кабелю: %.2f А\n", c.Current)
+	fmt.Fprintf(&b, "Втрата напруги: %.2f %%\n", c.VoltageDrop)
+	fmt.Fprintf(&b, "Втрати активної потужності: %.2f кВт (%.2f %%)\n", c.PowerLoss, c.LossPercent)
+	fmt.Fprintf(&b, "Час найбільших втрат: %.0f год/рік\n", c.LossHours)
+	fmt.Fprintf(&b, "Втрати енергії: %.0f кВт·год/рік (%.2f %% переданої енергії)\n", c.EnergyLoss, c.EnergyLossPart)
+	if c.Passed {
+		b.WriteString("Перевірку пройдено")
+		return b.String()
+	}
+	b.WriteString("ПЕРЕВІРКУ НЕ ПРОЙДЕНО:")
+	for _, violation := range c.Violations {
+		b.WriteString("\n  " + violation)
+	}
+	return b.String()
+}
+
+// Обробник сторінки перевірки лінії
+func task4Handler(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := template.ParseFiles("task4.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := LineCheckPageData{Sections: standardSections}
+
+	if r.Method == http.MethodPost {
+		params := LineCheckParams{
+			Material:   r.FormValue("material"),
+			Insulation: r.FormValue("insulation"),
+		}
+		err := parseFloatFields(r, []floatField{
+			{"voltage", &params.Voltage},
+			{"length", &params.Length},
+			{"activePower", &params.ActivePower},
+			{"reactivePower", &params.ReactivePower},
+			{"section", &params.Section},
+			{"utilizationHours", &params.UtilizationHours},
+		})
+		params.Cables, _ = strconv.Atoi(r.FormValue("cables"))
+		params.MaxVoltageDrop, _ = strconv.ParseFloat(r.FormValue("maxVoltageDrop"), 64)
+		params.MaxLossPercent, _ = strconv.ParseFloat(r.FormValue("maxLossPercent"), 64)
+		if err == nil {
+			var check LineCheck
+			check, err = checkLine(params)
+			if err == nil {
+				data.Result = check.report()
+			}
+		}
+		if err != nil {
+			data.Result = "Помилка: " + err.Error()
+		}
+	}
+	tmpl.Execute(w, data)
+}
+
+// Обробник API перевірки лінії
+func lineCheckAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := LineCheckParams{
+		Material:         defaultCableParams.Material,
+		Insulation:       defaultCableParams.Insulation,
+		UtilizationHours: defaultCableParams.UtilizationHours,
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	check, err := checkLine(params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(check)
+}
@@ -0,0 +1,513 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"math/cmplx"
+	"net/http"
+	"strings"
+)
+
+// режимні дані вузла для розрахунку усталеного режиму
+type LoadFlowBus struct {
+	ID               string  `json:"id"`
+	Type             string  `json:"type"`             // slack, pv або pq; за замовчуванням pq
+	Voltage          float64 `json:"voltage"`          // задана напруга для slack і pv, в. о.; 0 — 1
+	Angle            float64 `json:"angle"`            // кут балансуючого вузла, градуси
+	LoadActive       float64 `json:"loadActive"`       // МВт
+	LoadReactive     float64 `json:"loadReactive"`     // Мвар
+	GenerationActive float64 `json:"generationActive"` // МВт
+	// генерація реактивної потужності для pq, Мвар
+	GenerationReactive float64 `json:"generationReactive"`
+	// межі реактивної потужності pv-вузла, Мвар; якщо не задані, не перевіряються
+	ReactiveMin float64 `json:"reactiveMin"`
+	ReactiveMax float64 `json:"reactiveMax"`
+}
+
+// запит розрахунку усталеного режиму; схема береться з опису мережі для розрахунку КЗ
+type LoadFlowRequest struct {
+	Network       Network       `json:"network"`
+	Buses         []LoadFlowBus `json:"buses"`         // вузли без режимних даних вважаються pq без навантаження
+	Tolerance     float64       `json:"tolerance"`     // допустимий небаланс потужності, МВт/Мвар; 0 — 0,001
+	MaxIterations int           `json:"maxIterations"` // 0 — 20
+}
+
+// результати для вузла
+type LoadFlowBusResult struct {
+	ID                 string  `json:"id"`
+	Type               string  `json:"type"`
+	Magnitude          float64 `json:"magnitude"` // в. о.
+	Voltage            float64 `json:"voltage"`   // кВ
+	Angle              float64 `json:"angle"`     // градуси
+	GenerationActive   float64 `json:"generationActive"`
+	GenerationReactive float64 `json:"generationReactive"`
+	LoadActive         float64 `json:"loadActive"`
+	LoadReactive       float64 `json:"loadReactive"`
+}
+
+// потоки потужності у вітці
+type BranchFlow struct {
+	ID             string  `json:"id"`
+	From           string  `json:"from"`
+	To             string  `json:"to"`
+	ActiveFrom     float64 `json:"activeFrom"`   // МВт
+	ReactiveFrom   float64 `json:"reactiveFrom"` // Мвар
+	ActiveTo       float64 `json:"activeTo"`
+	ReactiveTo     float64 `json:"reactiveTo"`
+	ActiveLosses   float64 `json:"activeLosses"`
+	ReactiveLosses float64 `json:"reactiveLosses"`
+	Current        float64 `json:"current"` // кА на початку вітки
+}
+
+// діагностика збіжності
+type LoadFlowDiagnostics struct {
+	Converged     bool      `json:"converged"`
+	Iterations    int       `json:"iterations"`
+	Tolerance     float64   `json:"tolerance"`     // МВт/Мвар
+	Mismatches    []float64 `json:"mismatches"`    // найбільший небаланс на кожній ітерації, МВт/Мвар
+	WorstBus      string    `json:"worstBus"`      // вузол з найбільшим небалансом після останньої ітерації
+	SwitchedBuses []string  `json:"switchedBuses"` // pv-вузли, переведені в pq через межі реактивної потужності
+	Message       string    `json:"message"`
+}
+
+// результати розрахунку усталеного режиму
+type LoadFlowResult struct {
+	Buses              []LoadFlowBusResult `json:"buses"`
+	Branches           []BranchFlow        `json:"branches"`
+	GenerationActive   float64             `json:"generationActive"`
+	GenerationReactive float64             `json:"generationReactive"`
+	LoadActive         float64             `json:"loadActive"`
+	LoadReactive       float64             `json:"loadReactive"`
+	ActiveLosses       float64             `json:"activeLosses"`
+	ReactiveLosses     float64             `json:"reactiveLosses"`
+	Diagnostics        LoadFlowDiagnostics `json:"diagnostics"`
+}
+
+// дані сторінки розрахунку усталеного режиму
+type LoadFlowPageData struct {
+	Request string
+	Result  string
+}
+
+// приклад усталеного режиму для мережі зі сторінки розрахунку КЗ
+const exampleLoadFlow = `{
+  "network": {
+    "basePower": 100,
+    "buses": [
+      {"id": "B1", "name": "Шини 115 кВ", "voltage": 115},
+      {"id": "B2", "name": "Шини 115 кВ ПС-2", "voltage": 115},
+      {"id": "B3", "name": "Шини 10,5 кВ", "voltage": 10.5}
+    ],
+    "lines": [
+      {"id": "L1", "from": "B1", "to": "B2", "length": 40, "resistance": 0.12, "reactance": 0.4}
+    ],
+    "transformers": [
+      {"id": "T1", "from": "B2", "to": "B3", "power": 25, "uk": 10.5, "losses": 120}
+    ]
+  },
+  "buses": [
+    {"id": "B1", "type": "slack", "voltage": 1.05},
+    {"id": "B2", "type": "pq", "loadActive": 20, "loadReactive": 10},
+    {"id": "B3", "type": "pv", "voltage": 1.02, "generationActive": 10, "loadActive": 25, "loadReactive": 12, "reactiveMin": -5, "reactiveMax": 8}
+  ]
+}`
+
+// стан вузла під час ітерацій
+type flowBus struct {
+	kind        string
+	voltage     float64 // в. о.
+	angle       float64 // рад
+	scheduledP  float64 // в. о.
+	scheduledQ  float64
+	loadP       float64
+	loadQ       float64
+	reactiveMin float64
+	reactiveMax float64
+	limited     bool
+}
+
+// потужності, що втікають у вузли з мережі, в. о.
+func injections(y [][]complex128, voltages []complex128) []complex128 {
+	s := make([]complex128, len(voltages))
+	for i := range voltages {
+		var current complex128
+		for k := range voltages {
+			current += y[i][k] * voltages[k]
+		}
+		s[i] = voltages[i] * cmplx.Conj(current)
+	}
+	return s
+}
+
+// розв'язує систему лінійних рівнянь методом Гауса з вибором головного елемента
+func solveLinear(a [][]float64, b []float64) ([]float64, error) {
+	n := len(b)
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, errors.New("матриця Якобі вироджена")
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+		for row := col + 1; row < n; row++ {
+			factor := a[row][col] / a[col][col]
+			for k := col; k < n; k++ {
+				a[row][k] -= factor * a[col][k]
+			}
+			b[row] -= factor * b[col]
+		}
+	}
+	x := make([]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := b[row]
+		for k := row + 1; k < n; k++ {
+			sum -= a[row][k] * x[k]
+		}
+		x[row] = sum / a[row][row]
+	}
+	return x, nil
+}
+
+// режимні дані всіх вузлів схеми, зокрема нульових точок триобмоткових трансформаторів
+func (req LoadFlowRequest) flowBuses(m *networkModel) ([]flowBus, error) {
+	buses := make([]flowBus, m.size)
+	for i := range buses {
+		buses[i] = flowBus{kind: "pq", voltage: 1}
+	}
+	slack := 0
+	for _, data := range req.Buses {
+		i, ok := m.index[data.ID]
+		if !ok {
+			return nil, fmt.Errorf("режимні дані: невідомий вузол %q", data.ID)
+		}
+		b := &buses[i]
+		b.kind = strings.ToLower(data.Type)
+		if b.kind == "" {
+			b.kind = "pq"
+		}
+		if b.kind != "slack" && b.kind != "pv" && b.kind != "pq" {
+			return nil, fmt.Errorf("вузол %s: тип має бути slack, pv або pq", data.ID)
+		}
+		if b.kind != "pq" && data.Voltage > 0 {
+			b.voltage = data.Voltage
+		}
+		if b.kind == "slack" {
+			b.angle = data.Angle * math.Pi / 180
+			slack++
+		}
+		b.loadP = data.LoadActive / m.basePower
+		b.loadQ = data.LoadReactive / m.basePower
+		b.scheduledP = data.GenerationActive/m.basePower - b.loadP
+		b.scheduledQ = data.GenerationReactive/m.basePower - b.loadQ
+		b.reactiveMin = data.ReactiveMin / m.basePower
+		b.reactiveMax = data.ReactiveMax / m.basePower
+		b.limited = b.kind == "pv" && data.ReactiveMax > data.ReactiveMin
+	}
+	if slack != 1 {
+		return nil, fmt.Errorf("потрібен рівно один балансуючий вузол, задано %d", slack)
+	}
+	return buses, nil
+}
+
+// розраховує усталений режим методом Ньютона — Рафсона в полярних координатах
+func calculateLoadFlow(req LoadFlowRequest) (LoadFlowResult, error) {
+	m, err := req.Network.model(positiveSequence)
+	if err != nil {
+		return LoadFlowResult{}, err
+	}
+	// джерела схеми КЗ в усталеному режимі замінюються режимними даними вузлів
+	m.sources = nil
+	buses, err := req.flowBuses(m)
+	if err != nil {
+		return LoadFlowResult{}, err
+	}
+	if req.Tolerance <= 0 {
+		req.Tolerance = 0.001
+	}
+	if req.MaxIterations <= 0 {
+		req.MaxIterations = 20
+	}
+	y := m.admittance()
+	tolerance := req.Tolerance / m.basePower
+	diagnostics := LoadFlowDiagnostics{Tolerance: req.Tolerance}
+
+	voltages := func() []complex128 {
+		v := make([]complex128, len(buses))
+		for i, b := range buses {
+			v[i] = cmplx.Rect(b.voltage, b.angle)
+		}
+		return v
+	}
+
+	for iteration := 0; ; iteration++ {
+		s := injections(y, voltages())
+
+		// pv-вузол, що вийшов за межі реактивної потужності, фіксується на межі як pq
+		if iteration > 0 {
+			for i := range buses {
+				b := &buses[i]
+				if b.kind != "pv" || !b.limited {
+					continue
+				}
+				generation := imag(s[i]) + b.loadQ
+				if generation < b.reactiveMin || generation > b.reactiveMax {
+					b.kind = "pq"
+					b.scheduledQ = math.Max(b.reactiveMin, math.Min(generation, b.reactiveMax)) - b.loadQ
+					diagnostics.SwitchedBuses = append(diagnostics.SwitchedBuses, m.busName(i))
+				}
+			}
+		}
+
+		// невідомі: кути всіх вузлів, крім балансуючого, і модулі напруг pq-вузлів
+		var angles, magnitudes []int
+		for i, b := range buses {
+			if b.kind != "slack" {
+				angles = append(angles, i)
+			}
+			if b.kind == "pq" {
+				magnitudes = append(magnitudes, i)
+			}
+		}
+		mismatch := make([]float64, 0, len(angles)+len(magnitudes))
+		worst, worstValue := -1, 0.0
+		for _, i := range angles {
+			mismatch = append(mismatch, buses[i].scheduledP-real(s[i]))
+		}
+		for _, i := range magnitudes {
+			mismatch = append(mismatch, buses[i].scheduledQ-imag(s[i]))
+		}
+		for k, value := range mismatch {
+			if math.IsNaN(value) {
+				worstValue = math.NaN()
+				break
+			}
+			if math.Abs(value) > worstValue {
+				worstValue = math.Abs(value)
+				if k < len(angles) {
+					worst = angles[k]
+				} else {
+					worst = magnitudes[k-len(angles)]
+				}
+			}
+		}
+		diagnostics.Iterations = iteration
+		// небаланс понад 100 в. о. означає, що ітерації пішли від розв'язку
+		if math.IsNaN(worstValue) || worstValue > 100 {
+			diagnostics.Message = fmt.Sprintf("розрахунок розбігається на ітерації %d: ймовірно, навантаження перевищує пропускну здатність мережі", iteration)
+			break
+		}
+		diagnostics.Mismatches = append(diagnostics.Mismatches, worstValue*m.basePower)
+		if worst >= 0 {
+			diagnostics.WorstBus = m.busName(worst)
+		}
+		if worstValue <= tolerance {
+			diagnostics.Converged = true
+			diagnostics.Message = fmt.Sprintf("розрахунок зійшовся, ітерацій: %d", iteration)
+			break
+		}
+		if iteration == req.MaxIterations {
+			diagnostics.Message = fmt.Sprintf("розрахунок не зійшовся, ітерацій: %d; небаланс %.4g МВА у вузлі %s; перевірте навантаження та задані напруги",
+				iteration, worstValue*m.basePower, diagnostics.WorstBus)
+			break
+		}
+
+		jacobian := jacobianMatrix(y, voltages(), s, angles, magnitudes)
+		correction, err := solveLinear(jacobian, mismatch)
+		if err != nil {
+			diagnostics.Message = fmt.Sprintf("ітерація %d: %v — частина вузлів не з'єднана з балансуючим або задана некоректно", iteration, err)
+			break
+		}
+		for k, i := range angles {
+			buses[i].angle += correction[k]
+		}
+		for k, i := range magnitudes {
+			buses[i].voltage *= 1 + correction[len(angles)+k]
+		}
+	}
+
+	result := LoadFlowResult{Diagnostics: diagnostics}
+	if !diagnostics.Converged {
+		return result, nil
+	}
+
+	v := voltages()
+	s := injections(y, v)
+	for i, b := range buses {
+		if i >= len(m.buses) {
+			continue
+		}
+		bus := LoadFlowBusResult{
+			ID:                 m.buses[i].ID,
+			Type:               b.kind,
+			Magnitude:          b.voltage,
+			Voltage:            b.voltage * m.buses[i].Voltage,
+			Angle:              b.angle * 180 / math.Pi,
+			GenerationActive:   (real(s[i]) + b.loadP) * m.basePower,
+			GenerationReactive: (imag(s[i]) + b.loadQ) * m.basePower,
+			LoadActive:         b.loadP * m.basePower,
+			LoadReactive:       b.loadQ * m.basePower,
+		}
+		result.GenerationActive += bus.GenerationActive
+		result.GenerationReactive += bus.GenerationReactive
+		result.LoadActive += bus.LoadActive
+		result.LoadReactive += bus.LoadReactive
+		result.Buses = append(result.Buses, bus)
+	}
+	for _, b := range m.branches {
+		current := (v[b.from] - v[b.to]) / b.z
+		from := v[b.from] * cmplx.Conj(current) * complex(m.basePower, 0)
+		to := -v[b.to] * cmplx.Conj(current) * complex(m.basePower, 0)
+		flow := BranchFlow{
+			ID:             b.id,
+			From:           m.busName(b.from),
+			To:             m.busName(b.to),
+			ActiveFrom:     real(from),
+			ReactiveFrom:   imag(from),
+			ActiveTo:       real(to),
+			ReactiveTo:     imag(to),
+			ActiveLosses:   real(from + to),
+			ReactiveLosses: imag(from + to),
+			Current:        cmplx.Abs(current) * m.baseCurrent(b.voltageFrom),
+		}
+		result.ActiveLosses += flow.ActiveLosses
+		result.ReactiveLosses += flow.ReactiveLosses
+		result.Branches = append(result.Branches, flow)
+	}
+	return result, nil
+}
+
+// матриця Якобі для поправок кутів і відносних поправок модулів напруг
+func jacobianMatrix(y [][]complex128, v, s []complex128, angles, magnitudes []int) [][]float64 {
+	size := len(angles) + len(magnitudes)
+	j := make([][]float64, size)
+	for i := range j {
+		j[i] = make([]float64, size)
+	}
+	// dS_i/dθ_k та |V_k|·dS_i/d|V_k| через комплексні похідні
+	dTheta := func(i, k int) complex128 {
+		if i == k {
+			return complex(0, 1) * (s[i] - v[i]*cmplx.Conj(y[i][i]*v[i]))
+		}
+		return -complex(0, 1) * v[i] * cmplx.Conj(y[i][k]*v[k])
+	}
+	dMagnitude := func(i, k int) complex128 {
+		if i == k {
+			return s[i] + v[i]*cmplx.Conj(y[i][i]*v[i])
+		}
+		return v[i] * cmplx.Conj(y[i][k]*v[k])
+	}
+	rows := append(append([]int(nil), angles...), magnitudes...)
+	for r, i := range rows {
+		for c, k := range angles {
+			d := dTheta(i, k)
+			if r < len(angles) {
+				j[r][c] = real(d)
+			} else {
+				j[r][c] = imag(d)
+			}
+		}
+		for c, k := range magnitudes {
+			d := dMagnitude(i, k)
+			if r < len(angles) {
+				j[r][len(angles)+c] = real(d)
+			} else {
+				j[r][len(angles)+c] = imag(d)
+			}
+		}
+	}
+	return j
+}
+
+// текстовий звіт усталеного режиму
+func (r LoadFlowResult) report() string {
+	var b strings.Builder
+	d := r.Diagnostics
+	fmt.Fprintf(&b, "%s\n", d.Message)
+	for i, mismatch := range d.Mismatches {
+		fmt.Fprintf(&b, "  ітерація %d: найбільший небаланс %.6f МВА\n", i, mismatch)
+	}
+	if len(d.SwitchedBuses) > 0 {
+		fmt.Fprintf(&b, "  переведені в pq через межі реактивної потужності: %s\n", strings.Join(d.SwitchedBuses, ", "))
+	}
+	if !d.Converged {
+		return b.String()
+	}
+
+	b.WriteString("\nВузли:\n")
+	for _, bus := range r.Buses {
+		fmt.Fprintf(&b, "  %s (%s): U = %.4f в. о. = %.2f кВ ∠%.2f°, генерація %.2f МВт / %.2f Мвар, навантаження %.2f МВт / %.2f Мвар\n",
+			bus.ID, bus.Type, bus.Magnitude, bus.Voltage, bus.Angle,
+			bus.GenerationActive, bus.GenerationReactive, bus.LoadActive, bus.LoadReactive)
+	}
+	b.WriteString("\nВітки:\n")
+	for _, flow := range r.Branches {
+		fmt.Fprintf(&b, "  %s (%s → %s): %.2f МВт / %.2f Мвар → %.2f МВт / %.2f Мвар, I = %.3f кА, втрати %.3f МВт / %.3f Мвар\n",
+			flow.ID, flow.From, flow.To, flow.ActiveFrom, flow.ReactiveFrom, -flow.ActiveTo, -flow.ReactiveTo,
+			flow.Current, flow.ActiveLosses, flow.ReactiveLosses)
+	}
+	fmt.Fprintf(&b, "\nГенерація: %.2f МВт / %.2f Мвар\n", r.GenerationActive, r.GenerationReactive)
+	fmt.Fprintf(&b, "Навантаження: %.2f МВт / %.2f Мвар\n", r.LoadActive, r.LoadReactive)
+	fmt.Fprintf(&b, "Втрати: %.3f МВт / %.3f Мвар", r.ActiveLosses, r.ReactiveLosses)
+	return b.String()
+}
+
+// Обробник сторінки розрахунку усталеного режиму
+func task8Handler(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := template.ParseFiles("task8.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := LoadFlowPageData{Request: exampleLoadFlow}
+
+	if r.Method == http.MethodPost {
+		data.Request = r.FormValue("request")
+		var req LoadFlowRequest
+		err := json.Unmarshal([]byte(data.Request), &req)
+		if err != nil {
+			err = fmt.Errorf("некоректний опис режиму: %v", err)
+		} else {
+			var result LoadFlowResult
+			result, err = calculateLoadFlow(req)
+			if err == nil {
+				data.Result = result.report()
+			}
+		}
+		if err != nil {
+			data.Result = "Помилка: " + err.Error()
+		}
+	}
+	tmpl.Execute(w, data)
+}
+
+// Обробник API розрахунку усталеного режиму
+func loadFlowAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req LoadFlowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := calculateLoadFlow(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
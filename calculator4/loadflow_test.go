@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+// дві шини, лінія без втрат x = 0,1 в. о., навантаження 1 + j0 в. о.:
+// V2·sin δ = −0,1 і V2 = cos δ, звідки sin 2δ = −0,2
+func TestLoadFlowTwoBusesAnalytic(t *testing.T) {
+	req := LoadFlowRequest{
+		Network: Network{
+			BasePower: 100,
+			Buses:     []Bus{{ID: "B1", Voltage: 110}, {ID: "B2", Voltage: 110}},
+			Lines:     []Line{{ID: "L1", From: "B1", To: "B2", Length: 1, Reactance: 12.1}},
+		},
+		Buses: []LoadFlowBus{
+			{ID: "B1", Type: "slack", Voltage: 1},
+			{ID: "B2", LoadActive: 100},
+		},
+		Tolerance: 1e-6,
+	}
+	result, err := calculateLoadFlow(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Diagnostics.Converged {
+		t.Fatalf("розрахунок не зійшовся: %s", result.Diagnostics.Message)
+	}
+	delta := math.Asin(-0.2) / 2
+	bus := result.Buses[1]
+	if math.Abs(bus.Magnitude-math.Cos(delta)) > 1e-6 || math.Abs(bus.Angle-delta*180/math.Pi) > 1e-4 {
+		t.Errorf("V2 = %.6f ∠%.4f°, очікувалось %.6f ∠%.4f°", bus.Magnitude, bus.Angle, math.Cos(delta), delta*180/math.Pi)
+	}
+	if math.Abs(result.Buses[0].GenerationActive-100) > 1e-3 {
+		t.Errorf("генерація балансуючого вузла %.4f МВт, очікувалось 100 МВт (лінія без втрат)", result.Buses[0].GenerationActive)
+	}
+	if result.Diagnostics.Iterations > 5 {
+		t.Errorf("Ньютон — Рафсон мав зійтися квадратично, ітерацій: %d", result.Diagnostics.Iterations)
+	}
+}
+
+// трьохвузлова мережа прикладу: баланс потужності і межі реактивної потужності pv-вузла
+func TestLoadFlowExampleBalances(t *testing.T) {
+	var req LoadFlowRequest
+	if err := json.NewDecoder(strings.NewReader(exampleLoadFlow)).Decode(&req); err != nil {
+		t.Fatal(err)
+	}
+	result, err := calculateLoadFlow(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Diagnostics.Converged {
+		t.Fatalf("розрахунок не зійшовся: %s", result.Diagnostics.Message)
+	}
+	balance := result.GenerationActive - result.LoadActive - result.ActiveLosses
+	if math.Abs(balance) > 1e-2 {
+		t.Errorf("небаланс активної потужності %.4f МВт", balance)
+	}
+	for _, bus := range result.Buses {
+		if bus.Type == "pv" && (bus.GenerationReactive < -5-1e-6 || bus.GenerationReactive > 8+1e-6) {
+			t.Errorf("вузол %s: реактивна генерація %.3f Мвар поза межами [-5, 8]", bus.ID, bus.GenerationReactive)
+		}
+	}
+}
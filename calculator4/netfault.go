@@ -0,0 +1,465 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"math/cmplx"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// запит розрахунку КЗ у мережі
+type NetworkFaultRequest struct {
+	Network          Network `json:"network"`
+	Bus              string  `json:"bus"`              // вузол КЗ
+	Units            string  `json:"units"`            // pu — відносні одиниці, ohm — іменовані
+	ReferenceVoltage float64 `json:"referenceVoltage"` // напруга зведення для іменованих одиниць, кВ; 0 — напруга вузла КЗ
+	PrefaultVoltage  float64 `json:"prefaultVoltage"`  // доаварійна напруга, в. о.; 0 — 1
+	IEC              bool    `json:"iec"`              // додатково розрахувати за IEC 60909
+	VoltageFactor    float64 `json:"voltageFactor"`    // коефіцієнт напруги c; 0 — cmax за напругою вузла
+	MinimumTime      float64 `json:"minimumTime"`      // мінімальний час розмикання для струму вимкнення, с; 0 — 0,1
+	FaultDuration    float64 `json:"faultDuration"`    // тривалість КЗ для Ith, с; 0 — 1
+}
+
+// напруга вузла під час КЗ
+type BusVoltage struct {
+	Bus       string  `json:"bus"`
+	Magnitude float64 `json:"magnitude"` // в. о.
+	Voltage   float64 `json:"voltage"`   // лінійна, кВ
+	Angle     float64 `json:"angle"`     // градуси
+}
+
+// струм у вітці або джерелі під час КЗ
+type BranchCurrent struct {
+	ID          string  `json:"id"`
+	From        string  `json:"from"`
+	To          string  `json:"to"`
+	Current     float64 `json:"current"`     // в. о.
+	CurrentFrom float64 `json:"currentFrom"` // кА на ступені напруги початку вітки
+	CurrentTo   float64 `json:"currentTo"`   // кА на ступені напруги кінця вітки
+}
+
+// струми КЗ у вузлі без розподілу по вітках
+type BusFaultSummary struct {
+	Bus         string  `json:"bus"`
+	Voltage     float64 `json:"voltage"`
+	ThreePhase  float64 `json:"threePhase"`  // кА
+	TwoPhase    float64 `json:"twoPhase"`    // кА
+	SinglePhase float64 `json:"singlePhase"` // кА
+}
+
+// результати розрахунку КЗ у мережі
+type NetworkFaultResult struct {
+	Bus              string               `json:"bus"`
+	Voltage          float64              `json:"voltage"`
+	Units            string               `json:"units"`
+	ReferenceVoltage float64              `json:"referenceVoltage,omitempty"`
+	BusIDs           []string             `json:"busIds"`
+	ImpedanceMatrix  [][]Impedance        `json:"impedanceMatrix"`
+	Thevenin         Impedance            `json:"thevenin"`
+	TheveninNegative Impedance            `json:"theveninNegative"`
+	TheveninZero     Impedance            `json:"theveninZero"`
+	IsolatedNeutral  bool                 `json:"isolatedNeutral"` // у вузлі КЗ немає шляху для струмів нульової послідовності
+	ThreePhase       float64              `json:"threePhase"`      // кА
+	TwoPhase         float64              `json:"twoPhase"`        // кА
+	PeakFactor       float64              `json:"peakFactor"`      // ударний коефіцієнт мережі без двигунів
+	PeakCurrent      float64              `json:"peakCurrent"`     // ударний струм з урахуванням двигунів, кА
+	BreakingCurrent  float64              `json:"breakingCurrent"` // струм у момент розмикання, кА
+	Motors           []MotorContribution  `json:"motors"`
+	Unsymmetrical    []UnsymmetricalFault `json:"unsymmetrical"`
+	Voltages         []BusVoltage         `json:"voltages"`
+	Branches         []BranchCurrent      `json:"branches"`
+	Sources          []BranchCurrent      `json:"sources"`
+	TwoPhaseBranches []BranchCurrent      `json:"twoPhaseBranches"` // найбільший фазний струм за двофазного КЗ
+	TwoPhaseSources  []BranchCurrent      `json:"twoPhaseSources"`
+	Summary          []BusFaultSummary    `json:"summary"`
+	IEC              *IECResult           `json:"iec,omitempty"`
+}
+
+// дані сторінки розрахунку мережі
+type NetworkPageData struct {
+	Network string
+	Bus     string
+	Units   string
+	IEC     bool
+	Result  string
+}
+
+// приклад мережі для сторінки калькулятора
+const exampleNetwork = `{
+  "basePower": 100,
+  "buses": [
+    {"id": "B1", "name": "Шини 115 кВ", "voltage": 115},
+    {"id": "B2", "name": "Шини 115 кВ ПС-2", "voltage": 115},
+    {"id": "B3", "name": "Шини 10,5 кВ", "voltage": 10.5}
+  ],
+  "lines": [
+    {"id": "L1", "from": "B1", "to": "B2", "length": 40, "resistance": 0.12, "reactance": 0.4}
+  ],
+  "transformers": [
+    {"id": "T1", "from": "B2", "to": "B3", "power": 25, "uk": 10.5, "losses": 120}
+  ],
+  "generators": [
+    {"id": "G1", "bus": "B3", "power": 12.5, "reactance": 0.14, "xr": 40}
+  ],
+  "systems": [
+    {"id": "C1", "bus": "B1", "shortCircuitPower": 3000, "xr": 15}
+  ],
+  "motors": [
+    {"id": "M1", "bus": "B3", "power": 3200, "startingRatio": 5.5, "powerFactor": 0.87}
+  ]
+}`
+
+// назва вузла схеми заміщення, зокрема нульових точок триобмоткових трансформаторів
+func (m *networkModel) busName(i int) string {
+	switch {
+	case i < 0:
+		return "земля"
+	case i < len(m.buses):
+		return m.buses[i].ID
+	}
+	return "нульова точка"
+}
+
+// комплексний струм у вітці для заданих напруг вузлів; для джерела — від ЕРС до вузла
+func branchFlow(b branch, voltages []complex128, emf complex128) complex128 {
+	if b.to < 0 {
+		return (emf - voltages[b.from]) / b.z
+	}
+	return (voltages[b.from] - voltages[b.to]) / b.z
+}
+
+// струм у вітці для заданих напруг вузлів
+func (m *networkModel) branchCurrent(b branch, voltages []complex128, emf complex128) BranchCurrent {
+	return m.newBranchCurrent(b, cmplx.Abs(branchFlow(b, voltages, emf)))
+}
+
+// вітки схеми за ідентифікаторами
+func branchesByID(branches []branch) map[string]branch {
+	byID := make(map[string]branch, len(branches))
+	for _, b := range branches {
+		byID[b.id] = b
+	}
+	return byID
+}
+
+// найбільший фазний струм у вітці за двофазного КЗ із напруг прямої та зворотної послідовностей;
+// negativeBranches — вітки схеми зворотної послідовності, де джерела не мають ЕРС
+func (m *networkModel) twoPhaseBranchCurrent(b branch, negativeBranches map[string]branch, positive, negative []complex128, emf complex128) BranchCurrent {
+	// для пасивних віток опори прямої та зворотної послідовностей однакові
+	b2, ok := negativeBranches[b.id]
+	if !ok {
+		b2 = b
+	}
+	phases := phaseComponents(0, branchFlow(b, positive, emf), branchFlow(b2, negative, 0))
+	magnitude := 0.0
+	for _, current := range phases {
+		magnitude = math.Max(magnitude, cmplx.Abs(current))
+	}
+	return m.newBranchCurrent(b, magnitude)
+}
+
+// струм у вітці у відносних одиницях і в кА на ступенях напруги її кінців
+func (m *networkModel) newBranchCurrent(b branch, magnitude float64) BranchCurrent {
+	return BranchCurrent{
+		ID:          b.id,
+		From:        m.busName(b.from),
+		To:          m.busName(b.to),
+		Current:     magnitude,
+		CurrentFrom: magnitude * m.baseCurrent(b.voltageFrom),
+		CurrentTo:   magnitude * m.baseCurrent(b.voltageTo),
+	}
+}
+
+// схеми заміщення та матриці вузлових опорів трьох послідовностей
+func (n Network) sequenceImpedances() ([3]*networkModel, [3][][]complex128, error) {
+	var models [3]*networkModel
+	var matrices [3][][]complex128
+	for _, sequence := range []int{positiveSequence, negativeSequence, zeroSequence} {
+		model, err := n.model(sequence)
+		if err != nil {
+			return models, matrices, err
+		}
+		if len(model.sources) == 0 && sequence != zeroSequence {
+			return models, matrices, errors.New("у мережі немає жодного джерела: генератора чи системи")
+		}
+		matrices[sequence], err = model.impedance()
+		if err != nil {
+			return models, matrices, err
+		}
+		models[sequence] = model
+	}
+	return models, matrices, nil
+}
+
+// розраховує симетричні та несиметричні КЗ у вибраному вузлі та струми трифазного і двофазного КЗ в усіх вітках
+func calculateNetworkFault(req NetworkFaultRequest) (NetworkFaultResult, error) {
+	models, matrices, err := req.Network.sequenceImpedances()
+	if err != nil {
+		return NetworkFaultResult{}, err
+	}
+	m := models[positiveSequence]
+	fault, ok := m.index[req.Bus]
+	if !ok {
+		return NetworkFaultResult{}, fmt.Errorf("вузол КЗ %q не знайдено", req.Bus)
+	}
+	z, z2, z0 := matrices[positiveSequence], matrices[negativeSequence], matrices[zeroSequence]
+	if req.PrefaultVoltage <= 0 {
+		req.PrefaultVoltage = 1
+	}
+	if req.MinimumTime <= 0 {
+		req.MinimumTime = 0.1
+	}
+	emf := complex(req.PrefaultVoltage, 0)
+
+	// множник переведення опорів з відносних одиниць в Оми
+	result := NetworkFaultResult{Bus: req.Bus, Voltage: m.buses[fault].Voltage, Units: "pu"}
+	scale := 1.0
+	if req.Units == "ohm" {
+		result.Units = "ohm"
+		result.ReferenceVoltage = req.ReferenceVoltage
+		if result.ReferenceVoltage <= 0 {
+			result.ReferenceVoltage = result.Voltage
+		}
+		scale = result.ReferenceVoltage * result.ReferenceVoltage / m.basePower
+	}
+
+	for i, bus := range m.buses {
+		result.BusIDs = append(result.BusIDs, bus.ID)
+		row := make([]Impedance, len(m.buses))
+		for j := range m.buses {
+			row[j] = newImpedance(z[i][j] * complex(scale, 0))
+		}
+		result.ImpedanceMatrix = append(result.ImpedanceMatrix, row)
+
+		result.Summary = append(result.Summary, m.faultSummary(i, matrices, req.PrefaultVoltage))
+	}
+	result.Thevenin = result.ImpedanceMatrix[fault][fault]
+	result.TheveninNegative = newImpedance(z2[fault][fault] * complex(scale, 0))
+	result.TheveninZero = newImpedance(z0[fault][fault] * complex(scale, 0))
+	result.IsolatedNeutral = isolatedNeutral(z0[fault][fault])
+	result.ThreePhase = result.Summary[fault].ThreePhase
+	result.TwoPhase = result.Summary[fault].TwoPhase
+	result.Unsymmetrical = unsymmetricalFaults(z[fault][fault], z2[fault][fault], z0[fault][fault], emf,
+		m.baseCurrent(result.Voltage), result.Voltage)
+
+	voltages := faultVoltages(z, fault, emf)
+	for i, bus := range m.buses {
+		result.Voltages = append(result.Voltages, BusVoltage{
+			Bus:       bus.ID,
+			Magnitude: cmplx.Abs(voltages[i]),
+			Voltage:   cmplx.Abs(voltages[i]) * bus.Voltage,
+			Angle:     cmplx.Phase(voltages[i]) * 180 / math.Pi,
+		})
+	}
+	for _, b := range m.branches {
+		result.Branches = append(result.Branches, m.branchCurrent(b, voltages, emf))
+	}
+	for _, b := range m.sources {
+		result.Sources = append(result.Sources, m.branchCurrent(b, voltages, emf))
+	}
+
+	// двофазне КЗ: I1 = −I2 = E / (Z1 + Z2), V1 = E − Z1·I1, V2 = Z2·I1
+	twoPhaseCurrent := emf / (z[fault][fault] + z2[fault][fault])
+	positive := make([]complex128, m.size)
+	negative := make([]complex128, m.size)
+	for i := range positive {
+		positive[i] = emf - z[i][fault]*twoPhaseCurrent
+		negative[i] = z2[i][fault] * twoPhaseCurrent
+	}
+	negativeBranches := branchesByID(models[negativeSequence].branches)
+	negativeSources := branchesByID(models[negativeSequence].sources)
+	for _, b := range m.branches {
+		result.TwoPhaseBranches = append(result.TwoPhaseBranches, m.twoPhaseBranchCurrent(b, negativeBranches, positive, negative, emf))
+	}
+	for _, b := range m.sources {
+		result.TwoPhaseSources = append(result.TwoPhaseSources, m.twoPhaseBranchCurrent(b, negativeSources, positive, negative, emf))
+	}
+
+	result.Motors, result.PeakFactor, result.PeakCurrent, result.BreakingCurrent =
+		m.peakCurrent(req.Network, z, fault, voltages, emf, result.ThreePhase, req.MinimumTime)
+
+	if req.IEC {
+		if req.FaultDuration <= 0 {
+			req.FaultDuration = 1
+		}
+		iec, err := calculateIEC(req, fault, result)
+		if err != nil {
+			return NetworkFaultResult{}, err
+		}
+		result.IEC = &iec
+	}
+	return result, nil
+}
+
+// струми трифазного, двофазного та однофазного КЗ у вузлі i за діагоналями матриць Z1, Z2, Z0
+func (m *networkModel) faultSummary(i int, matrices [3][][]complex128, prefaultVoltage float64) BusFaultSummary {
+	z, z2, z0 := matrices[positiveSequence], matrices[negativeSequence], matrices[zeroSequence]
+	bus := m.buses[i]
+	base := m.baseCurrent(bus.Voltage)
+	return BusFaultSummary{
+		Bus:         bus.ID,
+		Voltage:     bus.Voltage,
+		ThreePhase:  prefaultVoltage / cmplx.Abs(z[i][i]) * base,
+		TwoPhase:    math.Sqrt(3) * prefaultVoltage / cmplx.Abs(z[i][i]+z2[i][i]) * base,
+		SinglePhase: 3 * prefaultVoltage / cmplx.Abs(z[i][i]+z2[i][i]+z0[i][i]) * base,
+	}
+}
+
+// напруги вузлів за методом накладання: V = E − Z·Iкз
+func faultVoltages(z [][]complex128, fault int, emf complex128) []complex128 {
+	faultCurrent := emf / z[fault][fault]
+	voltages := make([]complex128, len(z))
+	for i := range voltages {
+		voltages[i] = emf - z[i][fault]*faultCurrent
+	}
+	return voltages
+}
+
+// внески двигунів, ударний коефіцієнт мережі, ударний струм і струм вимкнення для КЗ у вузлі fault;
+// ударні струми мережі та двигунів складаються з власними коефіцієнтами,
+// а періодична складова від двигунів до моменту розмикання згасає
+func (m *networkModel) peakCurrent(n Network, z [][]complex128, fault int, voltages []complex128, emf complex128,
+	threePhase, minimumTime float64) ([]MotorContribution, float64, float64, float64) {
+	_, factor := peakFactor(z[fault][fault])
+	motors := motorContributions(n, m, voltages, emf, minimumTime, false)
+	networkCurrent := threePhase
+	breaking := threePhase
+	motorPeak := 0.0
+	for _, motor := range motors {
+		networkCurrent -= motor.Initial
+		motorPeak += motor.Peak
+		breaking -= motor.Initial - motor.Breaking
+	}
+	return motors, factor, math.Sqrt2*factor*math.Max(networkCurrent, 0) + motorPeak, breaking
+}
+
+// текстовий звіт для сторінки калькулятора
+func (r NetworkFaultResult) report() string {
+	var b strings.Builder
+	unit := "в. о."
+	if r.Units == "ohm" {
+		unit = fmt.Sprintf("Ом, зведено до %g кВ", r.ReferenceVoltage)
+	}
+	fmt.Fprintf(&b, "КЗ у вузлі %s (%g кВ)\n", r.Bus, r.Voltage)
+	fmt.Fprintf(&b, "Вхідні опори (%s):\n", unit)
+	fmt.Fprintf(&b, "  пряма послідовність: R = %.4f, X = %.4f\n", r.Thevenin.R, r.Thevenin.X)
+	fmt.Fprintf(&b, "  зворотна послідовність: R = %.4f, X = %.4f\n", r.TheveninNegative.R, r.TheveninNegative.X)
+	if r.IsolatedNeutral {
+		b.WriteString("  нульова послідовність: шлях через землю відсутній\n")
+	} else {
+		fmt.Fprintf(&b, "  нульова послідовність: R = %.4f, X = %.4f\n", r.TheveninZero.R, r.TheveninZero.X)
+	}
+	fmt.Fprintf(&b, "Струм трифазного КЗ: %.3f кА\nСтрум двофазного КЗ: %.3f кА\n", r.ThreePhase, r.TwoPhase)
+	fmt.Fprintf(&b, "Ударний струм: %.3f кА (kу мережі %.3f)\nСтрум у момент розмикання: %.3f кА\n",
+		r.PeakCurrent, r.PeakFactor, r.BreakingCurrent)
+	if len(r.Motors) > 0 {
+		b.WriteString("Підживлення від двигунів:\n" + motorReport(r.Motors))
+	}
+	for _, fault := range r.Unsymmetrical {
+		b.WriteString("\n" + fault.report())
+	}
+	if r.IEC != nil {
+		b.WriteString("\n" + r.IEC.report())
+	}
+
+	fmt.Fprintf(&b, "\nМатриця вузлових опорів (%s):\n", unit)
+	for i, row := range r.ImpedanceMatrix {
+		cells := make([]string, len(row))
+		for j, z := range row {
+			cells[j] = fmt.Sprintf("%.4f%+.4fj", z.R, z.X)
+		}
+		fmt.Fprintf(&b, "  %s: %s\n", r.BusIDs[i], strings.Join(cells, "  "))
+	}
+
+	b.WriteString("\nЗалишкові напруги вузлів:\n")
+	for _, v := range r.Voltages {
+		fmt.Fprintf(&b, "  %s: %.3f в. о. (%.2f кВ), %.1f°\n", v.Bus, v.Magnitude, v.Voltage, v.Angle)
+	}
+	b.WriteString("\nСтруми трифазного КЗ у вітках:\n")
+	for _, c := range r.Branches {
+		fmt.Fprintf(&b, "  %s (%s – %s): %.3f в. о., %.3f кА / %.3f кА\n", c.ID, c.From, c.To, c.Current, c.CurrentFrom, c.CurrentTo)
+	}
+	b.WriteString("\nПідживлення від джерел:\n")
+	for _, c := range r.Sources {
+		fmt.Fprintf(&b, "  %s (%s): %.3f кА\n", c.ID, c.From, c.CurrentFrom)
+	}
+	b.WriteString("\nСтруми двофазного КЗ у вітках (найбільший фазний):\n")
+	for _, c := range r.TwoPhaseBranches {
+		fmt.Fprintf(&b, "  %s (%s – %s): %.3f в. о., %.3f кА / %.3f кА\n", c.ID, c.From, c.To, c.Current, c.CurrentFrom, c.CurrentTo)
+	}
+	b.WriteString("\nПідживлення від джерел за двофазного КЗ:\n")
+	for _, c := range r.TwoPhaseSources {
+		fmt.Fprintf(&b, "  %s (%s): %.3f кА\n", c.ID, c.From, c.CurrentFrom)
+	}
+	b.WriteString("\nСтруми КЗ в усіх вузлах:\n")
+	for _, s := range r.Summary {
+		fmt.Fprintf(&b, "  %s (%g кВ): трифазне %.3f кА, двофазне %.3f кА, однофазне %.3f кА\n",
+			s.Bus, s.Voltage, s.ThreePhase, s.TwoPhase, s.SinglePhase)
+	}
+	return b.String()
+}
+
+// Обробник сторінки розрахунку мережі
+func task5Handler(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := template.ParseFiles("task5.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := NetworkPageData{Network: exampleNetwork, Bus: "B3", Units: "pu"}
+
+	if r.Method == http.MethodPost {
+		data.Network = r.FormValue("network")
+		data.Bus = r.FormValue("bus")
+		data.Units = r.FormValue("units")
+		data.IEC = r.FormValue("iec") != ""
+		req := NetworkFaultRequest{Bus: data.Bus, Units: data.Units, IEC: data.IEC}
+		req.ReferenceVoltage, _ = strconv.ParseFloat(r.FormValue("referenceVoltage"), 64)
+		req.VoltageFactor, _ = strconv.ParseFloat(r.FormValue("voltageFactor"), 64)
+		req.MinimumTime, _ = strconv.ParseFloat(r.FormValue("minimumTime"), 64)
+		req.FaultDuration, _ = strconv.ParseFloat(r.FormValue("faultDuration"), 64)
+		err := json.Unmarshal([]byte(data.Network), &req.Network)
+		if err != nil {
+			err = fmt.Errorf("некоректний опис мережі: %v", err)
+		} else {
+			var result NetworkFaultResult
+			result, err = calculateNetworkFault(req)
+			if err == nil {
+				data.Result = result.report()
+			}
+		}
+		if err != nil {
+			data.Result = "Помилка: " + err.Error()
+		}
+	}
+	tmpl.Execute(w, data)
+}
+
+// Обробник API розрахунку КЗ у мережі
+func networkFaultAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req NetworkFaultRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := calculateNetworkFault(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// у радіальній мережі весь струм двофазного КЗ проходить через джерело і лінію до точки КЗ
+func TestTwoPhaseBranchCurrentsRadialNetwork(t *testing.T) {
+	req := NetworkFaultRequest{
+		Network: Network{
+			BasePower: 100,
+			Buses:     []Bus{{ID: "B1", Voltage: 110}, {ID: "B2", Voltage: 110}},
+			Lines:     []Line{{ID: "L1", From: "B1", To: "B2", Length: 10, Resistance: 1.21, Reactance: 2.42}},
+			Systems:   []SystemEquivalent{{ID: "C1", Bus: "B1", ShortCircuitPower: 1000}},
+		},
+		Bus:   "B2",
+		Units: "pu",
+	}
+	result, err := calculateNetworkFault(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.TwoPhaseBranches) != 1 || len(result.TwoPhaseSources) != 1 {
+		t.Fatalf("вітки %+v, джерела %+v", result.TwoPhaseBranches, result.TwoPhaseSources)
+	}
+	for _, c := range []BranchCurrent{result.TwoPhaseBranches[0], result.TwoPhaseSources[0]} {
+		if math.Abs(c.CurrentTo-result.TwoPhase) > 1e-9 && math.Abs(c.CurrentFrom-result.TwoPhase) > 1e-9 {
+			t.Errorf("%s: струм %v кА, очікувалось %v кА", c.ID, c.CurrentFrom, result.TwoPhase)
+		}
+	}
+}
@@ -0,0 +1,441 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"strings"
+)
+
+// базисна потужність за замовчуванням, МВА
+const defaultBasePower = 100.0
+
+// послідовності симетричних складових
+const (
+	positiveSequence = iota
+	negativeSequence
+	zeroSequence
+)
+
+// номінальний коефіцієнт потужності генератора за замовчуванням
+const defaultGeneratorPowerFactor = 0.8
+
+// провідність до землі, якою заземлюються вузли без шляху для струмів нульової послідовності
+const isolatedAdmittance = 1e-9
+
+// вузол мережі
+type Bus struct {
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	Voltage float64 `json:"voltage"` // середня номінальна напруга ступеня, кВ
+}
+
+// лінія електропередачі
+type Line struct {
+	ID         string  `json:"id"`
+	From       string  `json:"from"`
+	To         string  `json:"to"`
+	Length     float64 `json:"length"`     // км
+	Resistance float64 `json:"resistance"` // Ом/км
+	Reactance  float64 `json:"reactance"`  // Ом/км
+	// опори нульової послідовності, Ом/км; якщо не задані, приймаються втричі більшими за опори прямої
+	ResistanceZero float64 `json:"resistanceZero"`
+	ReactanceZero  float64 `json:"reactanceZero"`
+}
+
+// двообмотковий трансформатор
+type Transformer struct {
+	ID     string  `json:"id"`
+	From   string  `json:"from"`   // вузол ВН
+	To     string  `json:"to"`     // вузол НН
+	Power  float64 `json:"power"`  // МВА
+	Uk     float64 `json:"uk"`     // %
+	Losses float64 `json:"losses"` // втрати КЗ, кВт
+	// схема з'єднання обмоток ВН/НН: YN, Y або D; за замовчуванням YN/D
+	Connection string  `json:"connection"`
+	ZeroRatio  float64 `json:"zeroRatio"` // Z0/Z1; за замовчуванням 1
+}
+
+// триобмотковий трансформатор; напруги КЗ задаються для пар обмоток
+type ThreeWindingTransformer struct {
+	ID           string  `json:"id"`
+	High         string  `json:"high"`
+	Medium       string  `json:"medium"`
+	Low          string  `json:"low"`
+	Power        float64 `json:"power"`        // МВА
+	UkHighMedium float64 `json:"ukHighMedium"` // %
+	UkHighLow    float64 `json:"ukHighLow"`    // %
+	UkMediumLow  float64 `json:"ukMediumLow"`  // %
+	Connection   string  `json:"connection"`   // схема з'єднання ВН/СН/НН; за замовчуванням YN/YN/D
+}
+
+// синхронний генератор
+type Generator struct {
+	ID        string  `json:"id"`
+	Bus       string  `json:"bus"`
+	Power     float64 `json:"power"`     // МВА
+	Reactance float64 `json:"reactance"` // надперехідний опір x''d, в. о. номінальної потужності
+	XR        float64 `json:"xr"`        // відношення X/R; 0 — активним опором нехтують
+	// опори зворотної та нульової послідовностей, в. о.; якщо не задані, дорівнюють x''d
+	NegativeReactance float64 `json:"negativeReactance"`
+	ZeroReactance     float64 `json:"zeroReactance"`
+	Grounded          bool    `json:"grounded"`    // нейтраль генератора заземлена
+	PowerFactor       float64 `json:"powerFactor"` // номінальний cos φ; за замовчуванням 0,8
+}
+
+// еквівалент енергосистеми, заданий потужністю КЗ на шинах приєднання
+type SystemEquivalent struct {
+	ID                string  `json:"id"`
+	Bus               string  `json:"bus"`
+	ShortCircuitPower float64 `json:"shortCircuitPower"` // МВА
+	XR                float64 `json:"xr"`
+	ZeroRatio         float64 `json:"zeroRatio"` // X0/X1; за замовчуванням 1
+}
+
+// група асинхронних або синхронних двигунів, що підживлюють місце КЗ
+type MotorGroup struct {
+	ID            string  `json:"id"`
+	Bus           string  `json:"bus"`
+	Kind          string  `json:"kind"`          // induction або synchronous; за замовчуванням induction
+	Power         float64 `json:"power"`         // сумарна номінальна активна потужність групи, кВт
+	StartingRatio float64 `json:"startingRatio"` // кратність пускового струму Iп/Iном
+	PowerFactor   float64 `json:"powerFactor"`   // номінальний cos φ
+	Efficiency    float64 `json:"efficiency"`    // ККД, в. о.; за замовчуванням 0,95
+	PolePairs     int     `json:"polePairs"`     // кількість пар полюсів; за замовчуванням 2
+	TimeConstant  float64 `json:"timeConstant"`  // стала часу згасання періодичної складової, с; за замовчуванням 0,04
+}
+
+// опис мережі
+type Network struct {
+	BasePower     float64                   `json:"basePower"` // МВА
+	Buses         []Bus                     `json:"buses"`
+	Lines         []Line                    `json:"lines"`
+	Transformers  []Transformer             `json:"transformers"`
+	Transformers3 []ThreeWindingTransformer `json:"transformers3"`
+	Generators    []Generator               `json:"generators"`
+	Systems       []SystemEquivalent        `json:"systems"`
+	Motors        []MotorGroup              `json:"motors"`
+}
+
+// вітка схеми заміщення у відносних одиницях; вузол -1 — земля
+type branch struct {
+	id          string
+	from, to    int
+	z           complex128
+	voltageFrom float64 // напруги ступенів на кінцях вітки для переведення струмів у кА
+	voltageTo   float64
+}
+
+// схема заміщення мережі у відносних одиницях
+type networkModel struct {
+	basePower float64
+	buses     []Bus
+	index     map[string]int
+	size      int // кількість вузлів разом з нульовими точками триобмоткових трансформаторів
+	branches  []branch
+	sources   []branch // вітки джерел до землі
+	sequence  int
+}
+
+// активний і реактивний опори за модулем та відношенням X/R
+func impedanceFromXR(z, xr float64) complex128 {
+	if xr <= 0 {
+		return complex(0, z)
+	}
+	r := z / math.Sqrt(1+xr*xr)
+	return complex(r, r*xr)
+}
+
+// розбирає схему з'єднання обмоток на зразок YN/D
+func parseConnection(text string, windings int, fallback string) ([]string, error) {
+	if strings.TrimSpace(text) == "" {
+		text = fallback
+	}
+	parts := strings.Split(strings.ToUpper(strings.ReplaceAll(text, " ", "")), "/")
+	if len(parts) != windings {
+		return nil, fmt.Errorf("схема з'єднання %q має описувати %d обмотки через «/»", text, windings)
+	}
+	for _, part := range parts {
+		if part != "YN" && part != "Y" && part != "D" {
+			return nil, fmt.Errorf("схема з'єднання %q: допустимі обмотки YN, Y та D", text)
+		}
+	}
+	return parts, nil
+}
+
+// будує схему заміщення заданої послідовності
+func (n Network) model(sequence int) (*networkModel, error) {
+	if n.BasePower <= 0 {
+		n.BasePower = defaultBasePower
+	}
+	if len(n.Buses) == 0 {
+		return nil, errors.New("мережа не містить жодного вузла")
+	}
+	m := &networkModel{basePower: n.BasePower, buses: n.Buses, index: map[string]int{}, sequence: sequence}
+	for i, bus := range n.Buses {
+		if bus.ID == "" || bus.Voltage <= 0 {
+			return nil, fmt.Errorf("вузол %d: потрібні ідентифікатор і додатна напруга", i+1)
+		}
+		if _, ok := m.index[bus.ID]; ok {
+			return nil, fmt.Errorf("вузол %q описано двічі", bus.ID)
+		}
+		m.index[bus.ID] = i
+	}
+	m.size = len(n.Buses)
+
+	find := func(element, id string) (int, error) {
+		i, ok := m.index[id]
+		if !ok {
+			return 0, fmt.Errorf("%s: невідомий вузол %q", element, id)
+		}
+		return i, nil
+	}
+	sb := n.BasePower
+
+	for _, line := range n.Lines {
+		from, err := find("лінія "+line.ID, line.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := find("лінія "+line.ID, line.To)
+		if err != nil {
+			return nil, err
+		}
+		u := n.Buses[from].Voltage
+		r, x := line.Resistance, line.Reactance
+		if sequence == zeroSequence {
+			r, x = line.ResistanceZero, line.ReactanceZero
+			if r == 0 && x == 0 {
+				r, x = 3*line.Resistance, 3*line.Reactance
+			}
+		}
+		z := complex(r*line.Length, x*line.Length) * complex(sb/(u*u), 0)
+		if z == 0 {
+			return nil, fmt.Errorf("лінія %s: опір не може бути нульовим", line.ID)
+		}
+		m.branches = append(m.branches, branch{id: line.ID, from: from, to: to, z: z, voltageFrom: u, voltageTo: n.Buses[to].Voltage})
+	}
+
+	for _, t := range n.Transformers {
+		from, err := find("трансформатор "+t.ID, t.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := find("трансформатор "+t.ID, t.To)
+		if err != nil {
+			return nil, err
+		}
+		if t.Power <= 0 || t.Uk <= 0 {
+			return nil, fmt.Errorf("трансформатор %s: потужність і напруга КЗ мають бути додатними", t.ID)
+		}
+		connection, err := parseConnection(t.Connection, 2, "YN/D")
+		if err != nil {
+			return nil, fmt.Errorf("трансформатор %s: %v", t.ID, err)
+		}
+		zAbs := t.Uk / 100 * sb / t.Power
+		r := t.Losses / 1000 / t.Power * sb / t.Power
+		if r >= zAbs {
+			return nil, fmt.Errorf("трансформатор %s: втрати КЗ не узгоджуються з напругою КЗ", t.ID)
+		}
+		z := complex(r, math.Sqrt(zAbs*zAbs-r*r))
+		b := branch{id: t.ID, from: from, to: to, z: z, voltageFrom: n.Buses[from].Voltage, voltageTo: n.Buses[to].Voltage}
+		if sequence == zeroSequence {
+			// струм нульової послідовності проходить через обмотку YN, а обмотка D замикає його на землю
+			ratio := t.ZeroRatio
+			if ratio <= 0 {
+				ratio = 1
+			}
+			b.z *= complex(ratio, 0)
+			switch {
+			case connection[0] == "YN" && connection[1] == "YN":
+			case connection[0] == "YN" && connection[1] == "D":
+				b.to = -1
+			case connection[0] == "D" && connection[1] == "YN":
+				b.from, b.to = to, -1
+				b.voltageFrom = b.voltageTo
+			default:
+				continue
+			}
+		}
+		m.branches = append(m.branches, b)
+	}
+
+	// триобмотковий трансформатор замінюється трипроменевою зіркою з окремою нульовою точкою
+	for _, t := range n.Transformers3 {
+		if t.Power <= 0 {
+			return nil, fmt.Errorf("трансформатор %s: потужність має бути додатною", t.ID)
+		}
+		connection, err := parseConnection(t.Connection, 3, "YN/YN/D")
+		if err != nil {
+			return nil, fmt.Errorf("трансформатор %s: %v", t.ID, err)
+		}
+		uk := []float64{
+			(t.UkHighMedium + t.UkHighLow - t.UkMediumLow) / 2,
+			(t.UkHighMedium + t.UkMediumLow - t.UkHighLow) / 2,
+			(t.UkHighLow + t.UkMediumLow - t.UkHighMedium) / 2,
+		}
+		star := m.size
+		m.size++
+		for i, winding := range []struct{ name, bus string }{{"ВН", t.High}, {"СН", t.Medium}, {"НН", t.Low}} {
+			bus, err := find("трансформатор "+t.ID, winding.bus)
+			if err != nil {
+				return nil, err
+			}
+			// від'ємні напруги КЗ променів можливі і відповідають реальним схемам; нульову замінюємо малим опором
+			x := uk[i] / 100 * sb / t.Power
+			if x == 0 {
+				x = 1e-6
+			}
+			u := n.Buses[bus].Voltage
+			b := branch{id: t.ID + "-" + winding.name, from: bus, to: star, z: complex(0, x), voltageFrom: u, voltageTo: u}
+			if sequence == zeroSequence {
+				switch connection[i] {
+				case "D":
+					b.from, b.to = star, -1
+				case "Y":
+					continue
+				}
+			}
+			m.branches = append(m.branches, b)
+		}
+	}
+
+	for _, g := range n.Generators {
+		bus, err := find("генератор "+g.ID, g.Bus)
+		if err != nil {
+			return nil, err
+		}
+		if g.Power <= 0 || g.Reactance <= 0 {
+			return nil, fmt.Errorf("генератор %s: потужність і x''d мають бути додатними", g.ID)
+		}
+		x := g.Reactance
+		switch {
+		case sequence == negativeSequence && g.NegativeReactance > 0:
+			x = g.NegativeReactance
+		case sequence == zeroSequence && !g.Grounded:
+			continue
+		case sequence == zeroSequence && g.ZeroReactance > 0:
+			x = g.ZeroReactance
+		}
+		z := impedanceFromXR(x*sb/g.Power, g.XR)
+		u := n.Buses[bus].Voltage
+		m.sources = append(m.sources, branch{id: g.ID, from: bus, to: -1, z: z, voltageFrom: u, voltageTo: u})
+	}
+
+	for _, s := range n.Systems {
+		bus, err := find("система "+s.ID, s.Bus)
+		if err != nil {
+			return nil, err
+		}
+		if s.ShortCircuitPower <= 0 {
+			return nil, fmt.Errorf("система %s: потужність КЗ має бути додатною", s.ID)
+		}
+		z := sb / s.ShortCircuitPower
+		if sequence == zeroSequence && s.ZeroRatio > 0 {
+			z *= s.ZeroRatio
+		}
+		u := n.Buses[bus].Voltage
+		m.sources = append(m.sources, branch{id: s.ID, from: bus, to: -1, z: impedanceFromXR(z, s.XR), voltageFrom: u, voltageTo: u})
+	}
+
+	// двигуни мають незаземлену нейтраль і в схему нульової послідовності не входять
+	for _, motor := range n.Motors {
+		bus, err := find("двигуни "+motor.ID, motor.Bus)
+		if err != nil {
+			return nil, err
+		}
+		motor = motor.withDefaults()
+		if err := motor.validate(); err != nil {
+			return nil, err
+		}
+		if sequence == zeroSequence {
+			continue
+		}
+		u := n.Buses[bus].Voltage
+		m.sources = append(m.sources, branch{id: motor.ID, from: bus, to: -1, z: motor.impedance(sb, u), voltageFrom: u, voltageTo: u})
+	}
+
+	return m, nil
+}
+
+// матриця вузлових провідностей
+func (m *networkModel) admittance() [][]complex128 {
+	y := make([][]complex128, m.size)
+	for i := range y {
+		y[i] = make([]complex128, m.size)
+	}
+	// гілки й джерела обходяться окремо, щоб append не записав джерела у спільний масив гілок
+	for _, branches := range [][]branch{m.branches, m.sources} {
+		for _, b := range branches {
+			admittance := 1 / b.z
+			y[b.from][b.from] += admittance
+			if b.to >= 0 {
+				y[b.to][b.to] += admittance
+				y[b.from][b.to] -= admittance
+				y[b.to][b.from] -= admittance
+			}
+		}
+	}
+	if m.sequence == zeroSequence {
+		for i := range y {
+			y[i][i] += isolatedAdmittance
+		}
+	}
+	return y
+}
+
+// матриця вузлових опорів як обернена до матриці провідностей
+func (m *networkModel) impedance() ([][]complex128, error) {
+	z, err := invertComplex(m.admittance())
+	if err != nil {
+		return nil, errors.New("матриця провідностей вироджена: перевірте, що кожен вузол зв'язаний з джерелом")
+	}
+	return z, nil
+}
+
+// обертає комплексну матрицю методом Гаусса–Жордана з вибором головного елемента
+func invertComplex(a [][]complex128) ([][]complex128, error) {
+	size := len(a)
+	work := make([][]complex128, size)
+	for i := range a {
+		work[i] = make([]complex128, 2*size)
+		copy(work[i], a[i])
+		work[i][size+i] = 1
+	}
+	for col := 0; col < size; col++ {
+		pivot := col
+		for row := col + 1; row < size; row++ {
+			if cmplx.Abs(work[row][col]) > cmplx.Abs(work[pivot][col]) {
+				pivot = row
+			}
+		}
+		if cmplx.Abs(work[pivot][col]) < 1e-12 {
+			return nil, errors.New("матриця вироджена")
+		}
+		work[col], work[pivot] = work[pivot], work[col]
+		scale := work[col][col]
+		for j := range work[col] {
+			work[col][j] /= scale
+		}
+		for row := 0; row < size; row++ {
+			if row == col || work[row][col] == 0 {
+				continue
+			}
+			factor := work[row][col]
+			for j := range work[row] {
+				work[row][j] -= factor * work[col][j]
+			}
+		}
+	}
+	inverse := make([][]complex128, size)
+	for i := range inverse {
+		inverse[i] = work[i][size:]
+	}
+	return inverse, nil
+}
+
+// базисний струм ступеня напруги, кА
+func (m *networkModel) baseCurrent(voltage float64) float64 {
+	return m.basePower / (math.Sqrt(3) * voltage)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"math/cmplx"
+	"testing"
+)
+
+func parseExampleNetwork(t *testing.T) Network {
+	t.Helper()
+	var n Network
+	if err := json.Unmarshal([]byte(exampleNetwork), &n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+// матриця вузлових опорів має бути оберненою до матриці провідностей: Y·Z = I
+func TestImpedanceInvertsAdmittance(t *testing.T) {
+	n := parseExampleNetwork(t)
+	for _, sequence := range []int{positiveSequence, negativeSequence, zeroSequence} {
+		m, err := n.model(sequence)
+		if err != nil {
+			t.Fatal(err)
+		}
+		y := m.admittance()
+		z, err := m.impedance()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range y {
+			for j := range y {
+				var product complex128
+				for k := range y {
+					product += y[i][k] * z[k][j]
+				}
+				want := complex(0, 0)
+				if i == j {
+					want = 1
+				}
+				if cmplx.Abs(product-want) > 1e-9 {
+					t.Errorf("послідовність %d: (Y·Z)[%d][%d] = %v, очікувалось %v", sequence, i, j, product, want)
+				}
+			}
+		}
+	}
+}
+
+// для двох вузлів, з'єднаних лінією, з джерелом у першому Z11 = zs, Z22 = zs + zл
+func TestImpedanceTwoBuses(t *testing.T) {
+	n := Network{
+		BasePower: 100,
+		Buses:     []Bus{{ID: "B1", Voltage: 110}, {ID: "B2", Voltage: 110}},
+		Lines:     []Line{{ID: "L1", From: "B1", To: "B2", Length: 10, Resistance: 1.21, Reactance: 2.42}},
+		Systems:   []SystemEquivalent{{ID: "C1", Bus: "B1", ShortCircuitPower: 1000}},
+	}
+	m, err := n.model(positiveSequence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	z, err := m.impedance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := m.sources[0].z
+	line := complex(0.1, 0.2)
+	if cmplx.Abs(z[0][0]-source) > 1e-9 || cmplx.Abs(z[1][1]-(source+line)) > 1e-9 || cmplx.Abs(z[0][1]-source) > 1e-9 {
+		t.Errorf("Z = %v, очікувалось Z11 = Z12 = %v, Z22 = %v", z, source, source+line)
+	}
+}
+
+// побудова матриці провідностей не повинна змінювати гілки моделі
+func TestAdmittanceKeepsBranches(t *testing.T) {
+	n := parseExampleNetwork(t)
+	m, err := n.model(positiveSequence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := len(m.branches)
+	m.branches = append(make([]branch, 0, count+len(m.sources)), m.branches...)
+	spare := m.branches[:cap(m.branches)]
+	m.admittance()
+	for i := count; i < len(spare); i++ {
+		if spare[i] != (branch{}) {
+			t.Fatalf("запасна ємність гілок змінена: %+v", spare[i])
+		}
+	}
+}
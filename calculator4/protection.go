@@ -0,0 +1,494 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"strings"
+)
+
+// характеристика залежної витримки часу за IEC 60255: t = TMS·k/((I/Is)^α − 1)
+type inverseCurve struct {
+	Name  string
+	K     float64
+	Alpha float64
+}
+
+var inverseCurves = map[string]inverseCurve{
+	"SI":  {"нормально інверсна", 0.14, 0.02},
+	"VI":  {"сильно інверсна", 13.5, 1},
+	"EI":  {"надзвичайно інверсна", 80, 2},
+	"LTI": {"довго інверсна", 120, 1},
+}
+
+// мінімальні коефіцієнти чутливості
+const (
+	minSensitivity              = 1.5 // МСЗ в основній зоні
+	minBackupSensitivity        = 1.2 // МСЗ в зоні резервування
+	minInstantaneousSensitivity = 1.2 // струмова відсічка в місці встановлення
+)
+
+// захист однієї ділянки радіальної мережі
+type ProtectionDevice struct {
+	ID                  string  `json:"id"`
+	Name                string  `json:"name"`
+	Bus                 string  `json:"bus,omitempty"`       // вузол встановлення
+	EndBus              string  `json:"endBus,omitempty"`    // вузол у кінці захищуваної ділянки
+	LoadCurrent         float64 `json:"loadCurrent"`         // максимальний робочий струм, А
+	MaxFaultEnd         float64 `json:"maxFaultEnd"`         // трифазний струм КЗ у кінці ділянки в максимальному режимі, кА
+	MinFaultEnd         float64 `json:"minFaultEnd"`         // двофазний струм КЗ у кінці ділянки в мінімальному режимі, кА
+	MinFaultStart       float64 `json:"minFaultStart"`       // двофазний струм КЗ у місці встановлення в мінімальному режимі, кА
+	Curve               string  `json:"curve"`               // SI, VI, EI або LTI
+	TimeMultiplier      float64 `json:"timeMultiplier"`      // TMS; 0 — підбирається за умовою селективності
+	ReliabilityFactor   float64 `json:"reliabilityFactor"`   // kн МСЗ; 0 — 1,2
+	SelfStartFactor     float64 `json:"selfStartFactor"`     // kсзп; 0 — 1,5
+	ReturnFactor        float64 `json:"returnFactor"`        // kв; 0 — 0,95
+	InstantaneousFactor float64 `json:"instantaneousFactor"` // kн відсічки; 0 — 1,2
+}
+
+// запит розрахунку уставок; захисти перелічуються від джерела до навантаження
+type ProtectionRequest struct {
+	Devices           []ProtectionDevice `json:"devices"`
+	Network           *Network           `json:"network,omitempty"`        // мережа максимального режиму для струмів КЗ
+	MinimalNetwork    *Network           `json:"minimalNetwork,omitempty"` // мережа мінімального режиму; порожньо — та сама
+	GradingMargin     float64            `json:"gradingMargin"`            // ступінь селективності, с; 0 — 0,3
+	InstantaneousTime float64            `json:"instantaneousTime"`        // час спрацювання відсічки, с; 0 — 0,05
+}
+
+// уставки та перевірка чутливості одного захисту
+type ProtectionSetting struct {
+	ID                       string   `json:"id"`
+	Name                     string   `json:"name"`
+	Curve                    string   `json:"curve"`
+	Pickup                   float64  `json:"pickup"`         // струм спрацювання МСЗ, А
+	TimeMultiplier           float64  `json:"timeMultiplier"` // TMS
+	Instantaneous            float64  `json:"instantaneous"`  // струм спрацювання відсічки, А
+	InstantaneousEnabled     bool     `json:"instantaneousEnabled"`
+	MaxFaultEnd              float64  `json:"maxFaultEnd"`   // кА
+	MinFaultEnd              float64  `json:"minFaultEnd"`   // кА
+	MinFaultStart            float64  `json:"minFaultStart"` // кА
+	Sensitivity              float64  `json:"sensitivity"`
+	BackupSensitivity        float64  `json:"backupSensitivity,omitempty"`
+	InstantaneousSensitivity float64  `json:"instantaneousSensitivity"`
+	Passed                   bool     `json:"passed"`
+	Violations               []string `json:"violations,omitempty"`
+}
+
+// перевірка селективності суміжних захистів
+type SelectivityCheck struct {
+	Upstream       string  `json:"upstream"`
+	Downstream     string  `json:"downstream"`
+	Current        float64 `json:"current"`        // струм з найменшим ступенем селективності, А
+	UpstreamTime   float64 `json:"upstreamTime"`   // с
+	DownstreamTime float64 `json:"downstreamTime"` // с
+	Margin         float64 `json:"margin"`         // с
+	Passed         bool    `json:"passed"`
+}
+
+// результати розрахунку релейного захисту
+type ProtectionResult struct {
+	GradingMargin     float64             `json:"gradingMargin"`
+	InstantaneousTime float64             `json:"instantaneousTime"`
+	Settings          []ProtectionSetting `json:"settings"`
+	Selectivity       []SelectivityCheck  `json:"selectivity"`
+	Passed            bool                `json:"passed"`
+	SVG               string              `json:"svg"` // карта селективності
+}
+
+// дані сторінки релейного захисту
+type ProtectionPageData struct {
+	Request string
+	Result  string
+	Chart   template.HTML
+}
+
+// приклад радіальної лінії 10 кВ для сторінки калькулятора
+const exampleProtection = `{
+  "gradingMargin": 0.3,
+  "devices": [
+    {"id": "Q1", "name": "Ввід 10 кВ", "loadCurrent": 1200, "maxFaultEnd": 15, "minFaultEnd": 10.5, "minFaultStart": 10.5, "curve": "SI"},
+    {"id": "Q2", "name": "Лінія W1", "loadCurrent": 300, "maxFaultEnd": 6, "minFaultEnd": 4.2, "minFaultStart": 10.5, "curve": "VI"},
+    {"id": "Q3", "name": "ТП-1", "loadCurrent": 60, "maxFaultEnd": 1.1, "minFaultEnd": 0.9, "minFaultStart": 4.2, "curve": "EI", "timeMultiplier": 0.1}
+  ]
+}`
+
+// час спрацювання за залежною характеристикою з TMS = 1; +Inf нижче струму спрацювання
+func (c inverseCurve) unitTime(current, pickup float64) float64 {
+	ratio := current / pickup
+	if ratio <= 1 {
+		return math.Inf(1)
+	}
+	return c.K / (math.Pow(ratio, c.Alpha) - 1)
+}
+
+// час спрацювання захисту з урахуванням відсічки
+func (s ProtectionSetting) operatingTime(current, instantaneousTime float64) float64 {
+	if s.InstantaneousEnabled && current >= s.Instantaneous {
+		return instantaneousTime
+	}
+	return s.TimeMultiplier * inverseCurves[s.Curve].unitTime(current, s.Pickup)
+}
+
+// струми КЗ у всіх вузлах мережі
+func busFaultSummary(n Network) (map[string]BusFaultSummary, error) {
+	if len(n.Buses) == 0 {
+		return nil, errors.New("мережа не містить вузлів")
+	}
+	fault, err := calculateNetworkFault(NetworkFaultRequest{Network: n, Bus: n.Buses[0].ID})
+	if err != nil {
+		return nil, err
+	}
+	summary := map[string]BusFaultSummary{}
+	for _, bus := range fault.Summary {
+		summary[bus.Bus] = bus
+	}
+	return summary, nil
+}
+
+// заповнює незадані струми КЗ розрахунком мережі максимального та мінімального режимів
+func (req *ProtectionRequest) faultCurrents() error {
+	if req.Network == nil {
+		return nil
+	}
+	maximal, err := busFaultSummary(*req.Network)
+	if err != nil {
+		return err
+	}
+	minimal := maximal
+	if req.MinimalNetwork != nil {
+		if minimal, err = busFaultSummary(*req.MinimalNetwork); err != nil {
+			return fmt.Errorf("мінімальний режим: %v", err)
+		}
+	}
+	lookup := func(summary map[string]BusFaultSummary, id string) (BusFaultSummary, error) {
+		bus, ok := summary[id]
+		if !ok {
+			return BusFaultSummary{}, fmt.Errorf("вузол %q не знайдено", id)
+		}
+		return bus, nil
+	}
+	for i := range req.Devices {
+		d := &req.Devices[i]
+		if d.EndBus != "" {
+			end, err := lookup(maximal, d.EndBus)
+			if err != nil {
+				return err
+			}
+			minEnd, err := lookup(minimal, d.EndBus)
+			if err != nil {
+				return err
+			}
+			if d.MaxFaultEnd <= 0 {
+				d.MaxFaultEnd = end.ThreePhase
+			}
+			if d.MinFaultEnd <= 0 {
+				d.MinFaultEnd = minEnd.TwoPhase
+			}
+		}
+		if d.Bus != "" && d.MinFaultStart <= 0 {
+			start, err := lookup(minimal, d.Bus)
+			if err != nil {
+				return err
+			}
+			d.MinFaultStart = start.TwoPhase
+		}
+	}
+	return nil
+}
+
+// розраховує уставки МСЗ і відсічок, чутливість та селективність захистів
+func calculateProtection(req ProtectionRequest) (ProtectionResult, error) {
+	if len(req.Devices) == 0 {
+		return ProtectionResult{}, errors.New("не задано жодного захисту")
+	}
+	if err := req.faultCurrents(); err != nil {
+		return ProtectionResult{}, err
+	}
+	if req.GradingMargin <= 0 {
+		req.GradingMargin = 0.3
+	}
+	if req.InstantaneousTime <= 0 {
+		req.InstantaneousTime = 0.05
+	}
+	result := ProtectionResult{GradingMargin: req.GradingMargin, InstantaneousTime: req.InstantaneousTime, Passed: true}
+
+	for _, d := range req.Devices {
+		if _, ok := inverseCurves[d.Curve]; !ok {
+			return ProtectionResult{}, fmt.Errorf("захист %s: характеристика має бути SI, VI, EI або LTI", d.ID)
+		}
+		if d.LoadCurrent <= 0 || d.MaxFaultEnd <= 0 || d.MinFaultEnd <= 0 {
+			return ProtectionResult{}, fmt.Errorf("захист %s: робочий струм і струми КЗ у кінці ділянки мають бути додатними", d.ID)
+		}
+		if d.ReliabilityFactor <= 0 {
+			d.ReliabilityFactor = 1.2
+		}
+		if d.SelfStartFactor <= 0 {
+			d.SelfStartFactor = 1.5
+		}
+		if d.ReturnFactor <= 0 || d.ReturnFactor > 1 {
+			d.ReturnFactor = 0.95
+		}
+		if d.InstantaneousFactor <= 0 {
+			d.InstantaneousFactor = 1.2
+		}
+
+		// МСЗ відстроюється від струму самозапуску, відсічка — від КЗ у кінці ділянки
+		s := ProtectionSetting{
+			ID:             d.ID,
+			Name:           d.Name,
+			Curve:          d.Curve,
+			Pickup:         d.ReliabilityFactor * d.SelfStartFactor / d.ReturnFactor * d.LoadCurrent,
+			TimeMultiplier: d.TimeMultiplier,
+			Instantaneous:  d.InstantaneousFactor * d.MaxFaultEnd * 1000,
+			MaxFaultEnd:    d.MaxFaultEnd,
+			MinFaultEnd:    d.MinFaultEnd,
+			MinFaultStart:  d.MinFaultStart,
+		}
+		s.Sensitivity = d.MinFaultEnd * 1000 / s.Pickup
+		if s.Sensitivity < minSensitivity {
+			s.Violations = append(s.Violations, fmt.Sprintf("чутливість МСЗ %.2f менша за %.1f", s.Sensitivity, minSensitivity))
+		}
+		s.InstantaneousSensitivity = d.MinFaultStart * 1000 / s.Instantaneous
+		s.InstantaneousEnabled = s.InstantaneousSensitivity >= minInstantaneousSensitivity
+		result.Settings = append(result.Settings, s)
+	}
+
+	// чутливість у зоні резервування: КЗ у кінці наступної ділянки
+	for i := 0; i+1 < len(result.Settings); i++ {
+		s := &result.Settings[i]
+		s.BackupSensitivity = result.Settings[i+1].MinFaultEnd * 1000 / s.Pickup
+		if s.BackupSensitivity < minBackupSensitivity {
+			s.Violations = append(s.Violations, fmt.Sprintf("чутливість у зоні резервування %.2f менша за %.1f", s.BackupSensitivity, minBackupSensitivity))
+		}
+	}
+
+	// TMS підбирається від навантаження до джерела, щоб витримати ступінь селективності
+	last := &result.Settings[len(result.Settings)-1]
+	if last.TimeMultiplier <= 0 {
+		last.TimeMultiplier = 0.05
+	}
+	checks := make([]SelectivityCheck, len(result.Settings)-1)
+	for i := len(result.Settings) - 2; i >= 0; i-- {
+		up, down := &result.Settings[i], result.Settings[i+1]
+		currents := coordinationCurrents(*up, down)
+		if up.TimeMultiplier <= 0 {
+			up.TimeMultiplier = 0.05
+			for _, current := range currents {
+				// там, де спрацьовує відсічка верхнього захисту, TMS не впливає на його час
+				if up.InstantaneousEnabled && current >= up.Instantaneous {
+					continue
+				}
+				required := (down.operatingTime(current, req.InstantaneousTime) + req.GradingMargin) /
+					inverseCurves[up.Curve].unitTime(current, up.Pickup)
+				up.TimeMultiplier = math.Max(up.TimeMultiplier, math.Ceil(required*100-1e-9)/100)
+			}
+		}
+
+		// без спільної зони дії (Current = 0) захисти селективні за струмом
+		check := SelectivityCheck{Upstream: up.ID, Downstream: down.ID, Passed: true}
+		for j, current := range currents {
+			upTime := up.operatingTime(current, req.InstantaneousTime)
+			downTime := down.operatingTime(current, req.InstantaneousTime)
+			if j == 0 || upTime-downTime < check.Margin {
+				check.Current, check.UpstreamTime, check.DownstreamTime, check.Margin = current, upTime, downTime, upTime-downTime
+			}
+		}
+		if len(currents) > 0 {
+			check.Passed = check.Margin >= req.GradingMargin-1e-9
+		}
+		checks[i] = check
+	}
+	result.Selectivity = checks
+
+	for i := range result.Settings {
+		s := &result.Settings[i]
+		s.Passed = len(s.Violations) == 0
+		result.Passed = result.Passed && s.Passed
+	}
+	for _, check := range result.Selectivity {
+		result.Passed = result.Passed && check.Passed
+	}
+	result.SVG = result.chart()
+	return result, nil
+}
+
+// струми, за якими перевіряється селективність: від спрацювання обох захистів до КЗ на початку наступної ділянки
+func coordinationCurrents(up, down ProtectionSetting) []float64 {
+	low := math.Max(up.Pickup, down.Pickup) * 1.01
+	high := up.MaxFaultEnd * 1000
+	if high <= low {
+		return nil
+	}
+	const points = 200
+	currents := make([]float64, 0, points+2)
+	for i := 0; i <= points; i++ {
+		currents = append(currents, low*math.Pow(high/low, float64(i)/points))
+	}
+	// найповільніша точка нижньої характеристики — безпосередньо перед відсічкою
+	if down.InstantaneousEnabled && down.Instantaneous > low && down.Instantaneous < high {
+		currents = append(currents, down.Instantaneous*0.999)
+	}
+	return currents
+}
+
+// карта селективності у логарифмічному масштабі
+func (r ProtectionResult) chart() string {
+	const (
+		width, height            = 640, 420
+		left, right, top, bottom = 60, 20, 20, 50
+		minTime, maxTime         = 0.01, 100.0
+	)
+	colors := []string{"#40190f", "#1f77b4", "#2ca02c", "#d62728", "#9467bd", "#ff7f0e"}
+
+	minCurrent, maxCurrent := math.Inf(1), 0.0
+	for _, s := range r.Settings {
+		minCurrent = math.Min(minCurrent, s.Pickup)
+		maxCurrent = math.Max(maxCurrent, math.Max(s.MaxFaultEnd, s.MinFaultStart)*1000)
+	}
+	minCurrent = math.Pow(10, math.Floor(math.Log10(minCurrent*0.8)))
+	maxCurrent = math.Pow(10, math.Ceil(math.Log10(maxCurrent*1.2)))
+
+	x := func(current float64) float64 {
+		return left + (width-left-right)*math.Log10(current/minCurrent)/math.Log10(maxCurrent/minCurrent)
+	}
+	y := func(t float64) float64 {
+		t = math.Max(minTime, math.Min(t, maxTime))
+		return top + (height-top-bottom)*math.Log10(maxTime/t)/math.Log10(maxTime/minTime)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Arial" font-size="11">`, width, height, width, height)
+	fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="white" stroke="#999"/>`, left, top, width-left-right, height-top-bottom)
+	for current := minCurrent; current <= maxCurrent*1.001; current *= 10 {
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="#ddd"/>`, x(current), top, x(current), height-bottom)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle">%g</text>`, x(current), height-bottom+15, current)
+	}
+	for t := minTime; t <= maxTime*1.001; t *= 10 {
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#ddd"/>`, left, y(t), width-right, y(t))
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end">%g</text>`, left-5, y(t)+4, t)
+	}
+	fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle">I, А</text>`, (width+left-right)/2, height-bottom+32)
+	fmt.Fprintf(&b, `<text x="15" y="%d" text-anchor="middle" transform="rotate(-90 15 %d)">t, с</text>`, (height+top-bottom)/2, (height+top-bottom)/2)
+
+	for i, s := range r.Settings {
+		color := colors[i%len(colors)]
+		var points []string
+		const steps = 100
+		end := maxCurrent
+		if s.InstantaneousEnabled {
+			end = s.Instantaneous
+		}
+		for j := 0; j <= steps; j++ {
+			current := s.Pickup * 1.01 * math.Pow(end/(s.Pickup*1.01), float64(j)/steps)
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x(current), y(s.operatingTime(current*0.9999, r.InstantaneousTime))))
+		}
+		if s.InstantaneousEnabled {
+			points = append(points,
+				fmt.Sprintf("%.1f,%.1f", x(s.Instantaneous), y(r.InstantaneousTime)),
+				fmt.Sprintf("%.1f,%.1f", x(maxCurrent), y(r.InstantaneousTime)))
+		}
+		fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="2" points="%s"/>`, color, strings.Join(points, " "))
+		fmt.Fprintf(&b, `<text x="%d" y="%d" fill="%s">%s %s</text>`, width-right-150, top+15+15*i, color,
+			template.HTMLEscapeString(s.ID), template.HTMLEscapeString(s.Name))
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// текстовий звіт уставок і перевірок
+func (r ProtectionResult) report() string {
+	var b strings.Builder
+	for _, s := range r.Settings {
+		fmt.Fprintf(&b, "%s %s (%s, %s):\n", s.ID, s.Name, s.Curve, inverseCurves[s.Curve].Name)
+		fmt.Fprintf(&b, "  МСЗ: Iсз = %.0f А, TMS = %.2f\n", s.Pickup, s.TimeMultiplier)
+		fmt.Fprintf(&b, "  чутливість у кінці ділянки: I(2)к.min = %.3f кА, kч = %.2f\n", s.MinFaultEnd, s.Sensitivity)
+		if s.BackupSensitivity > 0 {
+			fmt.Fprintf(&b, "  чутливість у зоні резервування: kч = %.2f\n", s.BackupSensitivity)
+		}
+		status := "вводиться"
+		if !s.InstantaneousEnabled {
+			status = "не ефективна, не вводиться"
+		}
+		fmt.Fprintf(&b, "  відсічка: Iсв = %.0f А (I(3)к.max = %.3f кА), kч = %.2f — %s\n",
+			s.Instantaneous, s.MaxFaultEnd, s.InstantaneousSensitivity, status)
+		for _, v := range s.Violations {
+			fmt.Fprintf(&b, "  увага: %s\n", v)
+		}
+	}
+	fmt.Fprintf(&b, "\nСелективність (ступінь %.2f с):\n", r.GradingMargin)
+	for _, check := range r.Selectivity {
+		mark := "так"
+		if !check.Passed {
+			mark = "НІ"
+		}
+		if check.Current == 0 {
+			fmt.Fprintf(&b, "  %s — %s: характеристики не перетинаються в зоні дії — %s\n", check.Upstream, check.Downstream, mark)
+			continue
+		}
+		fmt.Fprintf(&b, "  %s — %s: при I = %.0f А t = %.2f с і %.2f с, Δt = %.2f с — %s\n",
+			check.Upstream, check.Downstream, check.Current, check.UpstreamTime, check.DownstreamTime, check.Margin, mark)
+	}
+	if r.Passed {
+		b.WriteString("\nУставки задовольняють вимоги чутливості та селективності")
+	} else {
+		b.WriteString("\nУставки не задовольняють частину вимог")
+	}
+	return b.String()
+}
+
+// Обробник сторінки релейного захисту
+func task7Handler(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := template.ParseFiles("task7.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := ProtectionPageData{Request: exampleProtection}
+
+	if r.Method == http.MethodPost {
+		data.Request = r.FormValue("request")
+		var req ProtectionRequest
+		err := json.Unmarshal([]byte(data.Request), &req)
+		if err != nil {
+			err = fmt.Errorf("некоректний опис захистів: %v", err)
+		} else {
+			var result ProtectionResult
+			result, err = calculateProtection(req)
+			if err == nil {
+				data.Result = result.report()
+				data.Chart = template.HTML(result.SVG)
+			}
+		}
+		if err != nil {
+			data.Result = "Помилка: " + err.Error()
+		}
+	}
+	tmpl.Execute(w, data)
+}
+
+// Обробник API розрахунку релейного захисту
+func protectionAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ProtectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := calculateProtection(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
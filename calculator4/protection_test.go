@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// IEC 60255-151: t = TMS·k / ((I/Is)^α − 1); для SI при TMS = 1 і 10·Is t ≈ 2,97 с
+func TestInverseCurveUnitTime(t *testing.T) {
+	tests := []struct {
+		curve string
+		want  float64
+	}{
+		{curve: "SI", want: 2.9706},
+		{curve: "VI", want: 1.5},
+		{curve: "EI", want: 0.8081},
+		{curve: "LTI", want: 13.3333},
+	}
+	for _, tt := range tests {
+		if got := inverseCurves[tt.curve].unitTime(1000, 100); math.Abs(got-tt.want) > 1e-4 {
+			t.Errorf("%s: t(10·Is) = %.4f с, очікувалось %.4f с", tt.curve, got, tt.want)
+		}
+	}
+	if got := inverseCurves["SI"].unitTime(100, 100); !math.IsInf(got, 1) {
+		t.Errorf("при I = Is захист не повинен спрацьовувати, t = %v", got)
+	}
+
+	s := ProtectionSetting{Curve: "SI", Pickup: 100, TimeMultiplier: 0.2, Instantaneous: 2000, InstantaneousEnabled: true}
+	if got := s.operatingTime(1000, 0.05); math.Abs(got-0.2*2.9706) > 1e-4 {
+		t.Errorf("TMS = 0.2: t(10·Is) = %.4f с, очікувалось %.4f с", got, 0.2*2.9706)
+	}
+	if got := s.operatingTime(2500, 0.05); got != 0.05 {
+		t.Errorf("вище струму відсічки t = %v с, очікувалось 0.05 с", got)
+	}
+}
+
+// підібраний TMS дає ступінь селективності не менший за заданий, а на 0,01 менший — уже ні
+func TestProtectionGradingMargin(t *testing.T) {
+	var req ProtectionRequest
+	if err := json.Unmarshal([]byte(exampleProtection), &req); err != nil {
+		t.Fatal(err)
+	}
+	result, err := calculateProtection(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Selectivity) != 2 {
+		t.Fatalf("перевірок селективності %d, очікувалось 2", len(result.Selectivity))
+	}
+	for i, check := range result.Selectivity {
+		if !check.Passed || check.Margin < req.GradingMargin-1e-9 {
+			t.Errorf("%s–%s: ступінь селективності %.3f с, очікувалось не менше %.1f с", check.Upstream, check.Downstream, check.Margin, req.GradingMargin)
+		}
+		if math.Abs(check.UpstreamTime-check.DownstreamTime-check.Margin) > 1e-9 {
+			t.Errorf("%s–%s: %v − %v ≠ %v", check.Upstream, check.Downstream, check.UpstreamTime, check.DownstreamTime, check.Margin)
+		}
+
+		up, down := result.Settings[i], result.Settings[i+1]
+		up.TimeMultiplier -= 0.01
+		smallest := math.Inf(1)
+		for _, current := range coordinationCurrents(up, down) {
+			smallest = math.Min(smallest, up.operatingTime(current, result.InstantaneousTime)-down.operatingTime(current, result.InstantaneousTime))
+		}
+		if smallest >= req.GradingMargin {
+			t.Errorf("%s: TMS %.2f завеликий, ступінь %.3f с досягається і з TMS %.2f", up.ID, up.TimeMultiplier+0.01, smallest, up.TimeMultiplier)
+		}
+	}
+}
+
+// TMS не збільшується через струми, за яких верхній захист вимикає КЗ відсічкою
+func TestProtectionGradingSkipsUpstreamInstantaneousZone(t *testing.T) {
+	timeMultiplier := func(maxFaultEnd, instantaneousFactor float64) (float64, ProtectionResult) {
+		req := ProtectionRequest{Devices: []ProtectionDevice{
+			{ID: "Q1", LoadCurrent: 1200, MaxFaultEnd: maxFaultEnd, MinFaultEnd: 4.2, MinFaultStart: 10.5, Curve: "SI", InstantaneousFactor: instantaneousFactor},
+			{ID: "Q2", LoadCurrent: 300, MaxFaultEnd: 6, MinFaultEnd: 4.2, MinFaultStart: 3, Curve: "VI", TimeMultiplier: 0.5},
+		}}
+		result, err := calculateProtection(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result.Settings[0].TimeMultiplier, result
+	}
+
+	// в обох випадках відсічка Q1 — 7500 А, але в першому струми КЗ сягають 15 кА
+	withOverlap, result := timeMultiplier(15, 0.5)
+	withoutOverlap, _ := timeMultiplier(7.5, 1)
+	if withOverlap != withoutOverlap {
+		t.Errorf("TMS %v, очікувалось %v, як без струмів понад уставку відсічки", withOverlap, withoutOverlap)
+	}
+
+	// неселективність відсічки Q1 з МСЗ Q2 показується перевіркою
+	check := result.Selectivity[0]
+	if check.Passed || check.UpstreamTime != result.InstantaneousTime || check.Current < result.Settings[0].Instantaneous {
+		t.Errorf("перевірка %+v, очікувалась неселективність у зоні відсічки Q1", check)
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"net/http"
+	"strings"
+)
+
+// кутова частота мережі 50 Гц, рад/с
+const omega = 2 * math.Pi * 50
+
+// вхідні дані розрахунку КЗ в кінці лінії, живленої від системи через трансформатор
+type ShortCircuitParams struct {
+	SystemPower       float64 `json:"systemPower"`       // потужність КЗ системи, МВА
+	SystemZeroRatio   float64 `json:"systemZeroRatio"`   // X0/X1 системи, якщо трансформатора немає
+	Voltage           float64 `json:"voltage"`           // середня номінальна напруга в точці КЗ, кВ
+	TransformerPower  float64 `json:"transformerPower"`  // номінальна потужність трансформатора, МВА; 0 — без трансформатора
+	TransformerUk     float64 `json:"transformerUk"`     // напруга КЗ трансформатора, %
+	TransformerLosses float64 `json:"transformerLosses"` // втрати КЗ трансформатора, кВт
+	TransformerZero   float64 `json:"transformerZero"`   // Z0/Z1 трансформатора з боку точки КЗ; 0 — обмотка без заземленої нейтралі
+	LineLength        float64 `json:"lineLength"`        // довжина лінії до точки КЗ, км
+	LineResistance    float64 `json:"lineResistance"`    // питомий активний опір лінії, Ом/км
+	LineReactance     float64 `json:"lineReactance"`     // питомий індуктивний опір лінії, Ом/км
+	LineZeroRatio     float64 `json:"lineZeroRatio"`     // Z0/Z1 лінії
+	DisconnectionTime float64 `json:"disconnectionTime"` // час вимкнення КЗ, с
+}
+
+// струми одного виду КЗ
+type FaultCurrents struct {
+	Initial        float64 `json:"initial"`        // початковий діючий струм, кА
+	Peak           float64 `json:"peak"`           // ударний струм, кА
+	ThermalImpulse float64 `json:"thermalImpulse"` // тепловий імпульс, кА²·с
+}
+
+// опір у комплексній формі для відповіді API
+type Impedance struct {
+	R float64 `json:"r"`
+	X float64 `json:"x"`
+}
+
+func newImpedance(z complex128) Impedance {
+	return Impedance{R: real(z), X: imag(z)}
+}
+
+// результати розрахунку КЗ
+type ShortCircuitResult struct {
+	System       Impedance      `json:"system"`
+	Transformer  Impedance      `json:"transformer"`
+	Line         Impedance      `json:"line"`
+	Positive     Impedance      `json:"positive"` // сумарний опір прямої послідовності, Ом
+	Zero         Impedance      `json:"zero"`     // сумарний опір нульової послідовності, Ом
+	TimeConstant float64        `json:"timeConstant"`
+	PeakFactor   float64        `json:"peakFactor"`
+	ThreePhase   FaultCurrents  `json:"threePhase"`
+	SinglePhase  *FaultCurrents `json:"singlePhase,omitempty"` // відсутній, якщо нейтраль не заземлена
+}
+
+// значення за замовчуванням для необов'язкових полів
+var defaultShortCircuitParams = ShortCircuitParams{
+	SystemZeroRatio:   1,
+	LineZeroRatio:     3,
+	DisconnectionTime: 0.1,
+}
+
+// стала часу аперіодичної складової та ударний коефіцієнт;
+// для чисто реактивного кола kу = 2, а аперіодична складова в тепловому імпульсі не враховується
+func peakFactor(z complex128) (float64, float64) {
+	if real(z) <= 0 {
+		return 0, 2
+	}
+	timeConstant := imag(z) / (omega * real(z))
+	return timeConstant, 1 + math.Exp(-0.01/timeConstant)
+}
+
+// ударний струм і тепловий імпульс для початкового струму
+func faultCurrents(initial, timeConstant, factor, disconnectionTime float64) FaultCurrents {
+	return FaultCurrents{
+		Initial:        initial,
+		Peak:           math.Sqrt2 * factor * initial,
+		ThermalImpulse: initial * initial * (disconnectionTime + timeConstant),
+	}
+}
+
+// будує схему заміщення й розраховує трифазне та однофазне КЗ в кінці лінії
+func calculateShortCircuit(params ShortCircuitParams) (ShortCircuitResult, error) {
+	if params.SystemPower <= 0 || params.Voltage <= 0 {
+		return ShortCircuitResult{}, errors.New("потужність КЗ системи і напруга мають бути додатними")
+	}
+	if params.TransformerPower < 0 || params.LineLength < 0 || params.DisconnectionTime < 0 {
+		return ShortCircuitResult{}, errors.New("параметри трансформатора, лінії та час вимкнення не можуть бути від'ємними")
+	}
+	u := params.Voltage
+
+	// усі опори зведені до напруги точки КЗ, Ом
+	zs := complex(0, u*u/params.SystemPower)
+	var zt complex128
+	if params.TransformerPower > 0 {
+		if params.TransformerUk <= 0 {
+			return ShortCircuitResult{}, errors.New("напруга КЗ трансформатора має бути додатною")
+		}
+		zAbs := params.TransformerUk / 100 * u * u / params.TransformerPower
+		rt := params.TransformerLosses / 1000 * u * u / (params.TransformerPower * params.TransformerPower)
+		if rt >= zAbs {
+			return ShortCircuitResult{}, errors.New("втрати КЗ трансформатора не узгоджуються з напругою КЗ")
+		}
+		zt = complex(rt, math.Sqrt(zAbs*zAbs-rt*rt))
+	}
+	zl := complex(params.LineResistance*params.LineLength, params.LineReactance*params.LineLength)
+
+	z1 := zs + zt + zl
+	timeConstant, factor := peakFactor(z1)
+	result := ShortCircuitResult{
+		System:       newImpedance(zs),
+		Transformer:  newImpedance(zt),
+		Line:         newImpedance(zl),
+		Positive:     newImpedance(z1),
+		TimeConstant: timeConstant,
+		PeakFactor:   factor,
+	}
+
+	i3 := u / (math.Sqrt(3) * cmplx.Abs(z1))
+	result.ThreePhase = faultCurrents(i3, timeConstant, factor, params.DisconnectionTime)
+
+	// нульова послідовність замикається через нейтраль трансформатора або системи
+	var z0 complex128
+	grounded := true
+	switch {
+	case params.TransformerPower > 0 && params.TransformerZero > 0:
+		z0 = zt*complex(params.TransformerZero, 0) + zl*complex(params.LineZeroRatio, 0)
+	case params.TransformerPower == 0 && params.SystemZeroRatio > 0:
+		z0 = zs*complex(params.SystemZeroRatio, 0) + zl*complex(params.LineZeroRatio, 0)
+	default:
+		grounded = false
+	}
+	if grounded {
+		result.Zero = newImpedance(z0)
+		loop := 2*z1 + z0
+		i1 := math.Sqrt(3) * u / cmplx.Abs(loop)
+		loopTime, loopFactor := peakFactor(loop)
+		single := faultCurrents(i1, loopTime, loopFactor, params.DisconnectionTime)
+		result.SinglePhase = &single
+	}
+	return result, nil
+}
+
+// текстовий звіт для сторінки калькулятора
+func (r ShortCircuitResult) report() string {
+	var b strings.Builder
+	b.WriteString("Опори схеми заміщення, зведені до напруги точки КЗ:\n")
+	fmt.Fprintf(&b, "  система: X = %.4f Ом\n", r.System.X)
+	fmt.Fprintf(&b, "  трансформатор: R = %.4f Ом, X = %.4f Ом\n", r.Transformer.R, r.Transformer.X)
+	fmt.Fprintf(&b, "  лінія: R = %.4f Ом, X = %.4f Ом\n", r.Line.R, r.Line.X)
+	fmt.Fprintf(&b, "  сумарний: R = %.4f Ом, X = %.4f Ом\n", r.Positive.R, r.Positive.X)
+	fmt.Fprintf(&b, "Стала часу Та = %.4f с, ударний коефіцієнт kу = %.3f\n\n", r.TimeConstant, r.PeakFactor)
+	fmt.Fprintf(&b, "Трифазне КЗ:\n  початковий струм: %.3f кА\n  ударний струм: %.3f кА\n  тепловий імпульс: %.3f кА²·с",
+		r.ThreePhase.Initial, r.ThreePhase.Peak, r.ThreePhase.ThermalImpulse)
+	if r.SinglePhase == nil {
+		b.WriteString("\n\nОднофазне КЗ: нейтраль не заземлена, струм визначається ємністю мережі")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\n\nОднофазне КЗ (Z0: R = %.4f Ом, X = %.4f Ом):\n  початковий струм: %.3f кА\n  ударний струм: %.3f кА\n  тепловий імпульс: %.3f кА²·с",
+		r.Zero.R, r.Zero.X, r.SinglePhase.Initial, r.SinglePhase.Peak, r.SinglePhase.ThermalImpulse)
+	return b.String()
+}
+
+// Обробник API розрахунку КЗ
+func shortCircuitAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := defaultShortCircuitParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := calculateShortCircuit(params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
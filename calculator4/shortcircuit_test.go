@@ -0,0 +1,64 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// I(1) = √3·U / |2Z1 + Z0| для відомих опорів схеми
+func TestCalculateShortCircuitSinglePhase(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      ShortCircuitParams
+		threePhase  float64 // кА
+		singlePhase float64 // кА
+	}{
+		{
+			// Z1 = j1,1025 + (2 + j1,5) Ом, Z0 = j1,1025 + 3·(2 + j1,5) Ом, |2Z1 + Z0| = 14,7242 Ом
+			name: "система та лінія",
+			params: ShortCircuitParams{
+				SystemPower: 100, SystemZeroRatio: 1, Voltage: 10.5,
+				LineLength: 5, LineResistance: 0.4, LineReactance: 0.3, LineZeroRatio: 3,
+			},
+			threePhase:  1.8470,
+			singlePhase: 1.2351,
+		},
+		{
+			// Zт = 0,0662 + j1,1557 Ом, Z0 = Zт + 3·Zл
+			name: "система, трансформатор і лінія",
+			params: ShortCircuitParams{
+				SystemPower: 100, Voltage: 10.5,
+				TransformerPower: 10, TransformerUk: 10.5, TransformerLosses: 60, TransformerZero: 1,
+				LineLength: 5, LineResistance: 0.4, LineReactance: 0.3, LineZeroRatio: 3,
+			},
+			singlePhase: 1.0917,
+		},
+	}
+	for _, tt := range tests {
+		result, err := calculateShortCircuit(tt.params)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if result.SinglePhase == nil {
+			t.Fatalf("%s: немає однофазного КЗ", tt.name)
+		}
+		if math.Abs(result.SinglePhase.Initial-tt.singlePhase) > 1e-4 {
+			t.Errorf("%s: I(1) = %.4f кА, очікувалось %.4f кА", tt.name, result.SinglePhase.Initial, tt.singlePhase)
+		}
+		if tt.threePhase > 0 && math.Abs(result.ThreePhase.Initial-tt.threePhase) > 1e-4 {
+			t.Errorf("%s: I(3) = %.4f кА, очікувалось %.4f кА", tt.name, result.ThreePhase.Initial, tt.threePhase)
+		}
+	}
+}
+
+// обмотка без заземленої нейтралі не дає шляху для струму нульової послідовності
+func TestCalculateShortCircuitIsolatedNeutral(t *testing.T) {
+	params := ShortCircuitParams{SystemPower: 100, Voltage: 10.5, TransformerPower: 10, TransformerUk: 10.5}
+	result, err := calculateShortCircuit(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.SinglePhase != nil {
+		t.Errorf("однофазне КЗ %+v, очікувалось відсутнє", *result.SinglePhase)
+	}
+}
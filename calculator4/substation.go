@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+)
+
+// паспортні дані силового трансформатора
+type TransformerType struct {
+	Name        string  `json:"name"`
+	Power       float64 `json:"power"`       // номінальна потужність, МВА
+	HighVoltage float64 `json:"highVoltage"` // напруга обмотки ВН, кВ
+	LowVoltage  float64 `json:"lowVoltage"`  // напруга обмотки НН, кВ
+	Uk          float64 `json:"uk"`          // напруга КЗ, %
+	Losses      float64 `json:"losses"`      // втрати КЗ, кВт; 0 — активним опором нехтують
+}
+
+// каталог трансформаторів 110 кВ; перший збігається з даними, що раніше були зашиті в калькулятор
+var transformerCatalog = []TransformerType{
+	{Name: "ТМН-6300/110", Power: 6.3, HighVoltage: 115, LowVoltage: 11, Uk: 11.1},
+	{Name: "ТДН-10000/110", Power: 10, HighVoltage: 115, LowVoltage: 11, Uk: 10.5, Losses: 60},
+	{Name: "ТДН-16000/110", Power: 16, HighVoltage: 115, LowVoltage: 11, Uk: 10.5, Losses: 85},
+	{Name: "ТРДН-25000/110", Power: 25, HighVoltage: 115, LowVoltage: 10.5, Uk: 10.5, Losses: 120},
+	{Name: "ТРДН-40000/110", Power: 40, HighVoltage: 115, LowVoltage: 10.5, Uk: 10.5, Losses: 172},
+}
+
+// знаходить трансформатор у каталозі за назвою
+func findTransformer(name string) (TransformerType, bool) {
+	for _, transformer := range transformerCatalog {
+		if transformer.Name == name {
+			return transformer, true
+		}
+	}
+	return TransformerType{}, false
+}
+
+// вхідні дані перевірки підстанції
+type SubstationParams struct {
+	ResistanceNormal    float64         `json:"resistanceNormal"` // опори системи в нормальному режимі, Ом
+	ReactanceNormal     float64         `json:"reactanceNormal"`
+	ResistanceMinimal   float64         `json:"resistanceMinimal"` // опори системи в мінімальному режимі, Ом
+	ReactanceMinimal    float64         `json:"reactanceMinimal"`
+	ResistanceEmergency float64         `json:"resistanceEmergency"` // опори системи в аварійному режимі, Ом; обов'язкові за кількох трансформаторів
+	ReactanceEmergency  float64         `json:"reactanceEmergency"`
+	TransformerName     string          `json:"transformerName"` // назва з каталогу; порожня — дані вводяться вручну
+	Transformer         TransformerType `json:"transformer"`
+	Transformers        int             `json:"transformers"`     // кількість трансформаторів на підстанції
+	BusCouplerClosed    bool            `json:"busCouplerClosed"` // секційний вимикач НН увімкнений у нормальному режимі
+}
+
+// дані сторінки перевірки підстанції
+type SubstationPageData struct {
+	Catalog []TransformerType
+	Result  string
+}
+
+// струми КЗ в одному режимі
+type ModeCurrents struct {
+	Name           string  `json:"name"`
+	Available      bool    `json:"available"`
+	Note           string  `json:"note,omitempty"`
+	Transformers   int     `json:"transformers"` // трансформатори, що живлять точку КЗ паралельно
+	ThreePhaseHigh float64 `json:"threePhaseHigh"`
+	TwoPhaseHigh   float64 `json:"twoPhaseHigh"`
+	ThreePhaseLow  float64 `json:"threePhaseLow"`
+	TwoPhaseLow    float64 `json:"twoPhaseLow"`
+}
+
+// результати перевірки підстанції
+type SubstationResult struct {
+	Transformer TransformerType `json:"transformer"`
+	Resistance  float64         `json:"resistance"` // опір одного трансформатора, зведений до ВН, Ом
+	Reactance   float64         `json:"reactance"`
+	Modes       []ModeCurrents  `json:"modes"`
+}
+
+// струми КЗ для заданих опорів системи і кількості паралельних трансформаторів
+func modeCurrents(name string, rs, xs, rt, xt float64, transformers int, transformer TransformerType) ModeCurrents {
+	const multiplier = 1000.0
+	n := float64(transformers)
+	r, x := rs+rt/n, xs+xt/n
+	k := math.Pow(transformer.LowVoltage/transformer.HighVoltage, 2)
+
+	threeHigh := transformer.HighVoltage * multiplier / (math.Sqrt(3) * math.Hypot(r, x))
+	threeLow := transformer.LowVoltage * multiplier / (math.Sqrt(3) * math.Hypot(r*k, x*k))
+	return ModeCurrents{
+		Name:           name,
+		Available:      true,
+		Transformers:   transformers,
+		ThreePhaseHigh: threeHigh,
+		TwoPhaseHigh:   threeHigh * math.Sqrt(3) / 2,
+		ThreePhaseLow:  threeLow,
+		TwoPhaseLow:    threeLow * math.Sqrt(3) / 2,
+	}
+}
+
+// розраховує струми КЗ на шинах ВН і НН у нормальному, мінімальному та аварійному режимах
+func calculateSubstationCurrents(params SubstationParams) (SubstationResult, error) {
+	transformer := params.Transformer
+	if params.TransformerName != "" {
+		found, ok := findTransformer(params.TransformerName)
+		if !ok {
+			return SubstationResult{}, fmt.Errorf("трансформатора %q немає в каталозі", params.TransformerName)
+		}
+		transformer = found
+	}
+	if transformer.Name == "" {
+		transformer.Name = "введений вручну"
+	}
+	if transformer.Power <= 0 || transformer.HighVoltage <= 0 || transformer.LowVoltage <= 0 || transformer.Uk <= 0 {
+		return SubstationResult{}, errors.New("потужність, напруги обмоток і напруга КЗ трансформатора мають бути додатними")
+	}
+	if params.Transformers <= 0 {
+		params.Transformers = 1
+	}
+	// за двох і більше трансформаторів аварійний режим відрізняється від мінімального лише опорами системи
+	if params.Transformers > 1 && params.ResistanceEmergency <= 0 && params.ReactanceEmergency <= 0 {
+		return SubstationResult{}, errors.New("для підстанції з кількома трансформаторами задайте опори системи в аварійному режимі")
+	}
+
+	zt := transformer.Uk * math.Pow(transformer.HighVoltage, 2) / (100 * transformer.Power)
+	rt := transformer.Losses / 1000 * math.Pow(transformer.HighVoltage/transformer.Power, 2)
+	if rt >= zt {
+		return SubstationResult{}, errors.New("втрати КЗ трансформатора не узгоджуються з напругою КЗ")
+	}
+	xt := math.Sqrt(zt*zt - rt*rt)
+
+	// у нормальному режимі трансформатори працюють паралельно лише при увімкненому секційному вимикачі
+	normal := 1
+	if params.BusCouplerClosed {
+		normal = params.Transformers
+	}
+	result := SubstationResult{Transformer: transformer, Resistance: rt, Reactance: xt}
+	result.Modes = append(result.Modes,
+		modeCurrents("Нормальний режим", params.ResistanceNormal, params.ReactanceNormal, rt, xt, normal, transformer),
+		modeCurrents("Мінімальний режим", params.ResistanceMinimal, params.ReactanceMinimal, rt, xt, 1, transformer))
+
+	// в аварійному режимі один трансформатор вимкнений, а секції живляться від решти через секційний вимикач
+	if params.Transformers < 2 {
+		result.Modes = append(result.Modes, ModeCurrents{
+			Name: "Аварійний режим",
+			Note: "на підстанції один трансформатор, після його вимкнення шини НН знеструмлені",
+		})
+	} else {
+		emergency := modeCurrents("Аварійний режим", params.ResistanceEmergency, params.ReactanceEmergency,
+			rt, xt, params.Transformers-1, transformer)
+		emergency.Note = "один трансформатор вимкнений, секційний вимикач увімкнений"
+		result.Modes = append(result.Modes, emergency)
+	}
+	return result, nil
+}
+
+// текстовий звіт для сторінки калькулятора
+func (r SubstationResult) report() string {
+	var b strings.Builder
+	t := r.Transformer
+	fmt.Fprintf(&b, "Трансформатор %s: %.1f МВА, %.0f/%.1f кВ, uк = %.1f %%\n", t.Name, t.Power, t.HighVoltage, t.LowVoltage, t.Uk)
+	fmt.Fprintf(&b, "Опір трансформатора, зведений до %.0f кВ: R = %.2f Ом, X = %.2f Ом\n", t.HighVoltage, r.Resistance, r.Reactance)
+	for _, mode := range r.Modes {
+		fmt.Fprintf(&b, "\n%s", mode.Name)
+		if !mode.Available {
+			fmt.Fprintf(&b, ": %s\n", mode.Note)
+			continue
+		}
+		if mode.Note != "" {
+			fmt.Fprintf(&b, " (%s)", mode.Note)
+		}
+		fmt.Fprintf(&b, ", трансформаторів у роботі: %d\n", mode.Transformers)
+		fmt.Fprintf(&b, "  Струм трифазного КЗ, приведений до %.0f кВ: %.2f А\n", t.HighVoltage, mode.ThreePhaseHigh)
+		fmt.Fprintf(&b, "  Струм двофазного КЗ, приведений до %.0f кВ: %.2f А\n", t.HighVoltage, mode.TwoPhaseHigh)
+		fmt.Fprintf(&b, "  Дійсний струм трифазного КЗ на шинах %.1f кВ: %.2f А\n", t.LowVoltage, mode.ThreePhaseLow)
+		fmt.Fprintf(&b, "  Дійсний струм двофазного КЗ на шинах %.1f кВ: %.2f А\n", t.LowVoltage, mode.TwoPhaseLow)
+	}
+	return b.String()
+}
+
+// Обробник API перевірки підстанції
+func substationAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var params SubstationParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := calculateSubstationCurrents(params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// Обробник API каталогу трансформаторів
+func transformerCatalogHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transformerCatalog)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// трансформатор 6,3 МВА 115/11 кВ, uк = 11,1 %, втрати не враховуються: Xт = 233,01 Ом
+var testSubstationTransformer = TransformerType{Power: 6.3, HighVoltage: 115, LowVoltage: 11, Uk: 11.1}
+
+// в аварійному режимі точку КЗ живлять Transformers−1 трансформаторів через секційний вимикач
+func TestSubstationEmergencyModeUsesRemainingTransformers(t *testing.T) {
+	params := SubstationParams{
+		ResistanceNormal: 10.65, ReactanceNormal: 24.02,
+		ResistanceMinimal: 34.88, ReactanceMinimal: 65.68,
+		ResistanceEmergency: 12, ReactanceEmergency: 30,
+		Transformer:      testSubstationTransformer,
+		Transformers:     3,
+		BusCouplerClosed: true,
+	}
+	result, err := calculateSubstationCurrents(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		transformers int
+		threePhase   float64 // А, на стороні ВН
+	}{
+		{transformers: 3, threePhase: 649.36},
+		{transformers: 1},
+		{transformers: 2, threePhase: 451.68}, // 115 кВ / (√3·|12 + j(30 + 233,01/2)|)
+	}
+	if len(result.Modes) != len(want) {
+		t.Fatalf("режимів %d, очікувалось %d", len(result.Modes), len(want))
+	}
+	for i, w := range want {
+		mode := result.Modes[i]
+		if !mode.Available || mode.Transformers != w.transformers {
+			t.Errorf("%s: доступний %v, трансформаторів %d, очікувалось %d", mode.Name, mode.Available, mode.Transformers, w.transformers)
+		}
+		if w.threePhase > 0 && math.Abs(mode.ThreePhaseHigh-w.threePhase) > 0.01 {
+			t.Errorf("%s: I(3) = %.2f А, очікувалось %.2f А", mode.Name, mode.ThreePhaseHigh, w.threePhase)
+		}
+	}
+	if emergency := result.Modes[2]; math.Abs(emergency.TwoPhaseHigh-emergency.ThreePhaseHigh*math.Sqrt(3)/2) > 1e-9 {
+		t.Errorf("аварійний режим: I(2) = %v, очікувалось √3/2·I(3)", emergency.TwoPhaseHigh)
+	}
+}
+
+func TestSubstationEmergencyModeWithOneTransformer(t *testing.T) {
+	params := SubstationParams{
+		ResistanceNormal: 10.65, ReactanceNormal: 24.02,
+		ResistanceMinimal: 34.88, ReactanceMinimal: 65.68,
+		Transformer:  testSubstationTransformer,
+		Transformers: 1,
+	}
+	result, err := calculateSubstationCurrents(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if emergency := result.Modes[2]; emergency.Available {
+		t.Errorf("аварійний режим з одним трансформатором має бути недоступним: %+v", emergency)
+	}
+
+	params.Transformers = 2
+	if _, err := calculateSubstationCurrents(params); err == nil {
+		t.Error("для двох трансформаторів без опорів аварійного режиму очікувалась помилка")
+	}
+}
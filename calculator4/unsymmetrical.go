@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"strings"
+)
+
+// оператор повороту на 120°
+var rotation = cmplx.Rect(1, 2*math.Pi/3)
+
+// фазна величина в точці КЗ
+type PhaseQuantity struct {
+	Value   float64 `json:"value"`   // кА для струмів, кВ для фазних напруг
+	PerUnit float64 `json:"perUnit"` // в. о.
+	Angle   float64 `json:"angle"`   // градуси
+}
+
+// несиметричне КЗ у вузлі
+type UnsymmetricalFault struct {
+	Type         string           `json:"type"` // singlePhaseToGround, twoPhase або twoPhaseToGround
+	Name         string           `json:"name"`
+	Currents     [3]PhaseQuantity `json:"currents"`     // фази A, B, C
+	EarthCurrent float64          `json:"earthCurrent"` // 3I0, кА
+	Voltages     [3]PhaseQuantity `json:"voltages"`     // фазні напруги A, B, C
+	Note         string           `json:"note,omitempty"`
+}
+
+// фазні величини із симетричних складових
+func phaseComponents(zero, positive, negative complex128) [3]complex128 {
+	a, a2 := rotation, rotation*rotation
+	return [3]complex128{
+		zero + positive + negative,
+		zero + a2*positive + a*negative,
+		zero + a*positive + a2*negative,
+	}
+}
+
+func newPhaseQuantity(value complex128, base float64) PhaseQuantity {
+	return PhaseQuantity{
+		Value:   cmplx.Abs(value) * base,
+		PerUnit: cmplx.Abs(value),
+		Angle:   cmplx.Phase(value) * 180 / math.Pi,
+	}
+}
+
+// ознака відсутності шляху для струмів нульової послідовності
+func isolatedNeutral(z0 complex128) bool {
+	return cmplx.Abs(z0) > 0.1/isolatedAdmittance
+}
+
+// розраховує однофазне на землю, двофазне та двофазне на землю КЗ
+// за вхідними опорами послідовностей у вузлі КЗ; пошкоджені фаза A або фази B і C
+func unsymmetricalFaults(z1, z2, z0, emf complex128, baseCurrent, voltage float64) []UnsymmetricalFault {
+	phaseVoltage := voltage / math.Sqrt(3)
+
+	build := func(kind, name string, i1, i2, i0 complex128) UnsymmetricalFault {
+		fault := UnsymmetricalFault{Type: kind, Name: name, EarthCurrent: 3 * cmplx.Abs(i0) * baseCurrent}
+		currents := phaseComponents(i0, i1, i2)
+		voltages := phaseComponents(-z0*i0, emf-z1*i1, -z2*i2)
+		for phase := range currents {
+			fault.Currents[phase] = newPhaseQuantity(currents[phase], baseCurrent)
+			fault.Voltages[phase] = newPhaseQuantity(voltages[phase], phaseVoltage)
+		}
+		return fault
+	}
+
+	// однофазне КЗ: складові струму однакові, схеми послідовностей з'єднані послідовно
+	i1 := emf / (z1 + z2 + z0)
+	single := build("singlePhaseToGround", "Однофазне КЗ на землю (фаза A)", i1, i1, i1)
+	if isolatedNeutral(z0) {
+		single.Note = "нейтраль ізольована: струм замикання визначається ємністю мережі, напруга неушкоджених фаз зростає до лінійної"
+	}
+
+	// двофазне КЗ: схема нульової послідовності не бере участі
+	i1 = emf / (z1 + z2)
+	double := build("twoPhase", "Двофазне КЗ (фази B і C)", i1, -i1, 0)
+
+	// двофазне КЗ на землю: схеми зворотної та нульової послідовностей з'єднані паралельно
+	i1 = emf / (z1 + z2*z0/(z2+z0))
+	i2 := -i1 * z0 / (z2 + z0)
+	i0 := -i1 * z2 / (z2 + z0)
+	doubleGround := build("twoPhaseToGround", "Двофазне КЗ на землю (фази B і C)", i1, i2, i0)
+	if isolatedNeutral(z0) {
+		doubleGround.Note = "нейтраль ізольована: струми збігаються з двофазним КЗ"
+	}
+
+	return []UnsymmetricalFault{single, double, doubleGround}
+}
+
+// текстовий опис несиметричного КЗ
+func (f UnsymmetricalFault) report() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s:\n", f.Name)
+	for i, phase := range []string{"A", "B", "C"} {
+		fmt.Fprintf(&b, "  фаза %s: I = %.3f кА ∠%.1f°, U = %.2f кВ (%.3f в. о.) ∠%.1f°\n", phase,
+			f.Currents[i].Value, f.Currents[i].Angle, f.Voltages[i].Value, f.Voltages[i].PerUnit, f.Voltages[i].Angle)
+	}
+	fmt.Fprintf(&b, "  струм у землі 3I0 = %.3f кА\n", f.EarthCurrent)
+	if f.Note != "" {
+		fmt.Fprintf(&b, "  %s\n", f.Note)
+	}
+	return b.String()
+}
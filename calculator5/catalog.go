@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// показники надійності типу елемента
+type ReliabilityElement struct {
+	ID              string  `json:"id"`
+	Name            string  `json:"name"`
+	PerKm           bool    `json:"perKm"`           // показники задані на 1 км довжини
+	FailureRate     float64 `json:"failureRate"`     // ω, 1/рік
+	RecoveryTime    float64 `json:"recoveryTime"`    // tв, год
+	PlannedRate     float64 `json:"plannedRate"`     // частота планових ремонтів μ, 1/рік
+	PlannedDuration float64 `json:"plannedDuration"` // тривалість планового ремонту tп, год
+}
+
+// каталог елементів; змінюється через API і використовується всіма розрахунками
+var (
+	catalogMutex   sync.RWMutex
+	elementCatalog = []ReliabilityElement{
+		{ID: "overhead-line-110", Name: "ПЛ-110 кВ (на 1 км)", PerKm: true, FailureRate: 0.007, RecoveryTime: 10, PlannedRate: 0.167, PlannedDuration: 35},
+		{ID: "cable-line-10", Name: "КЛ-10 кВ (траншея, на 1 км)", PerKm: true, FailureRate: 0.03, RecoveryTime: 44, PlannedRate: 1, PlannedDuration: 9},
+		{ID: "transformer-110", Name: "Т-110 кВ", FailureRate: 0.015, RecoveryTime: 100, PlannedRate: 1, PlannedDuration: 43},
+		{ID: "transformer-35", Name: "Т-35 кВ", FailureRate: 0.01, RecoveryTime: 394.2, PlannedRate: 1, PlannedDuration: 35.04},
+		{ID: "breaker-110", Name: "В-110 кВ (елегазовий)", FailureRate: 0.01, RecoveryTime: 30, PlannedRate: 0.1, PlannedDuration: 30},
+		{ID: "breaker-10", Name: "В-10 кВ (малооливний)", FailureRate: 0.02, RecoveryTime: 15, PlannedRate: 0.33, PlannedDuration: 15},
+		{ID: "disconnector-110", Name: "Роз'єднувач 110 кВ", FailureRate: 0.01, RecoveryTime: 5, PlannedRate: 0.166, PlannedDuration: 18},
+		{ID: "bus-section-10", Name: "Секція шин 10 кВ", FailureRate: 0.03, RecoveryTime: 4, PlannedRate: 0.167, PlannedDuration: 5},
+		{ID: "connection-10", Name: "Приєднання 10 кВ", FailureRate: 0.03, RecoveryTime: 2, PlannedRate: 0.167, PlannedDuration: 5},
+	}
+)
+
+// повертає копію елемента каталогу
+func findElement(id string) (ReliabilityElement, error) {
+	catalogMutex.RLock()
+	defer catalogMutex.RUnlock()
+	for _, element := range elementCatalog {
+		if element.ID == id {
+			return element, nil
+		}
+	}
+	return ReliabilityElement{}, fmt.Errorf("елемента %q немає в каталозі", id)
+}
+
+// зміна елемента каталогу; незадані поля зберігають попередні значення
+type elementUpdate struct {
+	ID              string   `json:"id"`
+	Name            *string  `json:"name"`
+	PerKm           *bool    `json:"perKm"`
+	FailureRate     *float64 `json:"failureRate"`
+	RecoveryTime    *float64 `json:"recoveryTime"`
+	PlannedRate     *float64 `json:"plannedRate"`
+	PlannedDuration *float64 `json:"plannedDuration"`
+}
+
+// переносить задані поля зміни в елемент
+func (u elementUpdate) apply(element *ReliabilityElement) {
+	if u.Name != nil {
+		element.Name = *u.Name
+	}
+	if u.PerKm != nil {
+		element.PerKm = *u.PerKm
+	}
+	for _, field := range []struct {
+		value  *float64
+		target *float64
+	}{
+		{u.FailureRate, &element.FailureRate},
+		{u.RecoveryTime, &element.RecoveryTime},
+		{u.PlannedRate, &element.PlannedRate},
+		{u.PlannedDuration, &element.PlannedDuration},
+	} {
+		if field.value != nil {
+			*field.target = *field.value
+		}
+	}
+}
+
+// додає новий елемент каталогу або змінює задані поля наявного
+func updateElement(update elementUpdate) error {
+	if update.ID == "" {
+		return errors.New("потрібен ідентифікатор елемента")
+	}
+	catalogMutex.Lock()
+	defer catalogMutex.Unlock()
+
+	index := -1
+	for i := range elementCatalog {
+		if elementCatalog[i].ID == update.ID {
+			index = i
+			break
+		}
+	}
+	element := ReliabilityElement{ID: update.ID}
+	if index >= 0 {
+		element = elementCatalog[index]
+	} else if update.Name == nil || *update.Name == "" {
+		return fmt.Errorf("елемента %q немає в каталозі; для нового елемента потрібна назва", update.ID)
+	}
+	update.apply(&element)
+	if element.Name == "" {
+		return errors.New("назва елемента не може бути порожньою")
+	}
+	if element.FailureRate < 0 || element.RecoveryTime < 0 || element.PlannedRate < 0 || element.PlannedDuration < 0 {
+		return errors.New("показники надійності не можуть бути від'ємними")
+	}
+
+	if index >= 0 {
+		elementCatalog[index] = element
+	} else {
+		elementCatalog = append(elementCatalog, element)
+	}
+	return nil
+}
+
+// Обробник API каталогу: GET повертає каталог, POST додає елемент або змінює задані поля наявного
+func elementCatalogHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var update elementUpdate
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if err := updateElement(update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "Only GET and POST methods are allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	catalogMutex.RLock()
+	defer catalogMutex.RUnlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(elementCatalog)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// відновлює каталог після тесту, щоб зміни не впливали на інші тести
+func restoreCatalog(t *testing.T) {
+	t.Helper()
+	catalogMutex.RLock()
+	saved := append([]ReliabilityElement(nil), elementCatalog...)
+	catalogMutex.RUnlock()
+	t.Cleanup(func() {
+		catalogMutex.Lock()
+		elementCatalog = saved
+		catalogMutex.Unlock()
+	})
+}
+
+// часткова зміна має зберігати незадані поля елемента
+func TestUpdateElementKeepsOtherFields(t *testing.T) {
+	restoreCatalog(t)
+	before, err := findElement("transformer-110")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rate := 0.02
+	if err := updateElement(elementUpdate{ID: "transformer-110", FailureRate: &rate}); err != nil {
+		t.Fatal(err)
+	}
+	after, err := findElement("transformer-110")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := before
+	want.FailureRate = rate
+	if after != want {
+		t.Errorf("елемент %+v, очікувалось %+v", after, want)
+	}
+}
+
+func TestUpdateElementRejectsInvalid(t *testing.T) {
+	restoreCatalog(t)
+	rate := 0.01
+	negative := -1.0
+	empty := ""
+	cases := []struct {
+		name   string
+		update elementUpdate
+	}{
+		{"без ідентифікатора", elementUpdate{FailureRate: &rate}},
+		{"новий елемент без назви", elementUpdate{ID: "reactor-10", FailureRate: &rate}},
+		{"порожня назва", elementUpdate{ID: "breaker-10", Name: &empty}},
+		{"від'ємна частота відмов", elementUpdate{ID: "breaker-10", FailureRate: &negative}},
+		{"від'ємний час відновлення", elementUpdate{ID: "breaker-10", RecoveryTime: &negative}},
+		{"від'ємна тривалість ремонту", elementUpdate{ID: "breaker-10", PlannedDuration: &negative}},
+	}
+	for _, c := range cases {
+		if err := updateElement(c.update); err == nil {
+			t.Errorf("%s: зміна прийнята, очікувалась помилка", c.name)
+		}
+	}
+	if _, err := findElement("reactor-10"); err == nil {
+		t.Error("відхилений елемент потрапив до каталогу")
+	}
+	if element, _ := findElement("breaker-10"); element.FailureRate != 0.02 {
+		t.Errorf("частота відмов В-10 кВ %v після відхилених змін, очікувалось 0.02", element.FailureRate)
+	}
+}
+
+// одночасні читання та зміни каталогу через API не мають спричиняти гонок
+func TestElementCatalogConcurrentRequests(t *testing.T) {
+	restoreCatalog(t)
+	server := httptest.NewServer(http.HandlerFunc(elementCatalogHandler))
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			resp, err := http.Get(server.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer resp.Body.Close()
+			var catalog []ReliabilityElement
+			if err := json.NewDecoder(resp.Body).Decode(&catalog); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			body := strings.NewReader(`{"id": "connection-10", "recoveryTime": 2.5}`)
+			resp, err := http.Post(server.URL, "application/json", body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("код відповіді %d, очікувалось %d", resp.StatusCode, http.StatusOK)
+			}
+		}()
+	}
+	wg.Wait()
+
+	element, err := findElement("connection-10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if element.RecoveryTime != 2.5 || element.FailureRate != 0.03 {
+		t.Errorf("елемент %+v після змін через API", element)
+	}
+}
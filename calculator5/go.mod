@@ -0,0 +1,3 @@
+module calculator5
+
+go 1.21
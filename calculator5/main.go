@@ -1,9 +1,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"html/template"
 	"log"
+	"math"
 	"net/http"
 	"strconv"
 )
@@ -21,27 +23,87 @@ type TaskData struct {
 	Result string
 }
 
-func calculateReliabilitySingleLineSystem() float64 {
-	failureRates := []float64{0.01, 0.07, 0.015, 0.02, 0.03 * 6}
-	failureRateSum := 0.0
-	for _, rate := range failureRates {
-		failureRateSum += rate
+// показники надійності системи
+type ReliabilityIndicators struct {
+	FailureRate     float64 // ω, 1/рік
+	RecoveryTime    float64 // tв, год
+	EmergencyOutage float64 // kа, коефіцієнт аварійного простою
+	PlannedOutage   float64 // kп, коефіцієнт планового простою
+}
+
+// елемент розрахункової схеми: тип з каталогу, кількість і довжина для елементів,
+// показники яких задані на 1 км
+type schemeElement struct {
+	id     string
+	count  float64
+	length float64 // км
+}
+
+// показники послідовно з'єднаних елементів схеми за даними каталогу
+func seriesReliability(scheme []schemeElement) (ReliabilityIndicators, error) {
+	var result ReliabilityIndicators
+	weightedRecoveryTime := 0.0
+	maxPlannedOutage := 0.0
+	for _, item := range scheme {
+		element, err := findElement(item.id)
+		if err != nil {
+			return ReliabilityIndicators{}, err
+		}
+		failureRate := element.FailureRate * item.count
+		if element.PerKm {
+			failureRate *= item.length
+		}
+		result.FailureRate += failureRate
+		weightedRecoveryTime += failureRate * element.RecoveryTime
+		maxPlannedOutage = math.Max(maxPlannedOutage, element.PlannedRate*element.PlannedDuration)
 	}
-	meanRecoveryTime := (0.01*30 + 0.07*10 + 0.015*100 + 0.02*15 + 0.18*2) / failureRateSum
-	return (failureRateSum * meanRecoveryTime) / 8760
+	if result.FailureRate > 0 {
+		result.RecoveryTime = weightedRecoveryTime / result.FailureRate
+	}
+	result.EmergencyOutage = result.FailureRate * result.RecoveryTime / 8760
+	// плановий простій визначається найтривалішим ремонтом з запасом 20 %
+	result.PlannedOutage = 1.2 * maxPlannedOutage / 8760
+	return result, nil
 }
 
-func calculateReliabilityDoubleLineSystem() float64 {
-	failureRateSingleLine := 0.295
-	failureRateTwoLinesSimultaneous := 2*failureRateSingleLine*(13.6*1e-4) + 5.89*1e-7
-	return failureRateTwoLinesSimultaneous + 0.02
+func calculateReliabilitySingleLineSystem(lineLength float64, connectionCount int) (ReliabilityIndicators, error) {
+	if lineLength <= 0 || connectionCount < 0 {
+		return ReliabilityIndicators{}, errors.New("довжина лінії має бути додатною, а кількість приєднань — невід'ємною")
+	}
+	return seriesReliability([]schemeElement{
+		{id: "breaker-110", count: 1},
+		{id: "overhead-line-110", count: 1, length: lineLength},
+		{id: "transformer-110", count: 1},
+		{id: "breaker-10", count: 1},
+		{id: "connection-10", count: float64(connectionCount)},
+	})
 }
 
-func calculatePowerLoss(emergencyRate float64, plannedRate float64) float64 {
-	const emergencyPowerLoss = 14900.0
-	const plannedPowerLoss = 132400.0
+// частота відмов двоколової системи з секційним вимикачем 10 кВ: ωдк = 2ω(kа + kп) + ωсв,
+// де ω, kа і kп — показники одного кола за тими самими довжиною лінії та кількістю приєднань
+func calculateReliabilityDoubleLineSystem(single ReliabilityIndicators) (float64, error) {
+	sectionBreaker, err := findElement("breaker-10")
+	if err != nil {
+		return 0, err
+	}
+	failureRateTwoLinesSimultaneous := 2 * single.FailureRate * (single.EmergencyOutage + single.PlannedOutage)
+	return failureRateTwoLinesSimultaneous + sectionBreaker.FailureRate, nil
+}
 
-	return (emergencyRate * emergencyPowerLoss) + (plannedRate * plannedPowerLoss)
+// збитки від недовідпуску енергії через трансформатор 35 кВ з розрахунковим навантаженням
+// maxLoad (кВт) і часом використання максимуму maxLoadDuration (год)
+func calculatePowerLoss(emergencyRate, plannedRate, maxLoad, maxLoadDuration float64) (float64, error) {
+	if maxLoad <= 0 || maxLoadDuration <= 0 || maxLoadDuration > 8760 {
+		return 0, errors.New("навантаження має бути додатним, а час використання максимуму — в межах (0, 8760] год")
+	}
+	transformer, err := findElement("transformer-35")
+	if err != nil {
+		return 0, err
+	}
+	emergencyPowerLoss := transformer.FailureRate * transformer.RecoveryTime / 8760 * maxLoad * maxLoadDuration
+	plannedPowerLoss := transformer.PlannedRate * transformer.PlannedDuration / 8760 * maxLoad * maxLoadDuration
+
+	return (emergencyRate * emergencyPowerLoss) + (plannedRate * plannedPowerLoss), nil
 }
 
 func task1Handler(w http.ResponseWriter, r *http.Request) {
@@ -54,20 +116,38 @@ func task1Handler(w http.ResponseWriter, r *http.Request) {
 	data := TaskData{}
 
 	if r.Method == http.MethodPost {
-		singleLineReliability := calculateReliabilitySingleLineSystem()
-		doubleLineReliability := calculateReliabilityDoubleLineSystem()
+		lineLength, err1 := strconv.ParseFloat(r.FormValue("lineLength"), 64)
+		connectionCount, err2 := strconv.Atoi(r.FormValue("connectionCount"))
 
-		moreReliableSystem := ""
-		if singleLineReliability > doubleLineReliability {
-			moreReliableSystem = "Одноколова система більш надійна."
-		} else {
-			moreReliableSystem = "Двоколова система більш надійна."
+		var single ReliabilityIndicators
+		var doubleLineFailureRate float64
+		if err1 == nil && err2 == nil {
+			single, err = calculateReliabilitySingleLineSystem(lineLength, connectionCount)
+			if err == nil {
+				doubleLineFailureRate, err = calculateReliabilityDoubleLineSystem(single)
+			}
 		}
 
-		data.Result = fmt.Sprintf(
-			"Надійність одноколової системи: %.6f\nНадійність двоколової системи: %.6f\n%s",
-			singleLineReliability, doubleLineReliability, moreReliableSystem,
-		)
+		if err1 != nil || err2 != nil {
+			data.Result = "Помилка: введіть коректні довжину лінії та кількість приєднань."
+		} else if err != nil {
+			data.Result = "Помилка: " + err.Error()
+		} else {
+			moreReliableSystem := ""
+			if single.FailureRate < doubleLineFailureRate {
+				moreReliableSystem = "Одноколова система більш надійна."
+			} else {
+				moreReliableSystem = "Двоколова система більш надійна."
+			}
+
+			data.Result = fmt.Sprintf(
+				"Одноколова система:\nЧастота відмов ω: %.4f рік⁻¹\nСередня тривалість відновлення tв: %.2f год\n"+
+					"Коефіцієнт аварійного простою kа: %.6f\nКоефіцієнт планового простою kп: %.6f\n"+
+					"Частота відмов двоколової системи: %.6f рік⁻¹\n%s",
+				single.FailureRate, single.RecoveryTime, single.EmergencyOutage, single.PlannedOutage,
+				doubleLineFailureRate, moreReliableSystem,
+			)
+		}
 	}
 	tmpl.Execute(w, data)
 }
@@ -87,12 +167,18 @@ func task2Handler(w http.ResponseWriter, r *http.Request) {
 
 		emergencyRate, err1 := strconv.ParseFloat(emergencyRateStr, 64)
 		plannedRate, err2 := strconv.ParseFloat(plannedRateStr, 64)
-
-		if err1 == nil && err2 == nil {
-			result := calculatePowerLoss(emergencyRate, plannedRate)
-			data.Result = fmt.Sprintf("Збитки: %.2f грн", result)
+		maxLoad, err3 := strconv.ParseFloat(r.FormValue("maxLoad"), 64)
+		maxLoadDuration, err4 := strconv.ParseFloat(r.FormValue("maxLoadDuration"), 64)
+
+		if err1 == nil && err2 == nil && err3 == nil && err4 == nil {
+			result, err := calculatePowerLoss(emergencyRate, plannedRate, maxLoad, maxLoadDuration)
+			if err != nil {
+				data.Result = "Помилка: " + err.Error()
+			} else {
+				data.Result = fmt.Sprintf("Збитки: %.2f грн", result)
+			}
 		} else {
-			data.Result = "Помилка: введіть коректні значення питомих збитків."
+			data.Result = "Помилка: введіть коректні значення питомих збитків, навантаження та часу використання максимуму."
 		}
 	}
 
@@ -103,6 +189,7 @@ func main() {
 	http.HandleFunc("/", homeHandler)
 	http.HandleFunc("/task1", task1Handler)
 	http.HandleFunc("/task2", task2Handler)
+	http.HandleFunc("/api/elements", elementCatalogHandler)
 
 	log.Println("Сервер запущено на http://localhost:8080")
 	http.ListenAndServe(":8080", nil)
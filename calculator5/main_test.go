@@ -0,0 +1,56 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// контрольний приклад: ПЛ-110 кВ довжиною 10 км і 6 приєднань 10 кВ
+func TestReliabilitySingleAndDoubleLine(t *testing.T) {
+	single, err := calculateReliabilitySingleLineSystem(10, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doubleLine, err := calculateReliabilityDoubleLineSystem(single)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		name      string
+		got, want float64
+		tolerance float64
+	}{
+		{"ω", single.FailureRate, 0.295, 1e-9},
+		{"kа", single.EmergencyOutage, 3.61e-4, 1e-6},
+		{"kп", single.PlannedOutage, 5.89e-3, 1e-5},
+		{"ωдс", doubleLine, 0.0237, 1e-4},
+	} {
+		if math.Abs(c.got-c.want) > c.tolerance {
+			t.Errorf("%s = %v, очікувалось %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+// довжина лінії та кількість приєднань мають бути допустимими
+func TestReliabilityRejectsInvalidInput(t *testing.T) {
+	if _, err := calculateReliabilitySingleLineSystem(0, 6); err == nil {
+		t.Error("нульова довжина лінії має відхилятися")
+	}
+	if _, err := calculateReliabilitySingleLineSystem(10, -1); err == nil {
+		t.Error("від'ємна кількість приєднань має відхилятися")
+	}
+}
+
+// контрольний приклад збитків: 23,6 і 17,6 грн/кВт·год, Pм = 5120 кВт, Tм = 6451 год
+func TestPowerLoss(t *testing.T) {
+	loss, err := calculatePowerLoss(23.6, 17.6, 5120, 6451)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(loss-2676019.30) > 0.01 {
+		t.Errorf("збитки %.2f грн, очікувалось 2676019.30 грн", loss)
+	}
+	if _, err := calculatePowerLoss(23.6, 17.6, 5120, 9000); err == nil {
+		t.Error("час використання максимуму понад 8760 год має відхилятися")
+	}
+}
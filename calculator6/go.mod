@@ -0,0 +1,3 @@
+module calculator6
+
+go 1.21